@@ -0,0 +1,163 @@
+package gterm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TextSink receives the accessible text stream, announcements and regions of
+// the screen read out as text, for screen readers, braille displays and logs
+type TextSink interface {
+	Say(text string) error
+}
+
+// WriterSink writes each announcement as a line, to stdout, a file or a pipe
+type WriterSink struct {
+	W io.Writer
+}
+
+// Say writes text followed by a newline
+func (sink WriterSink) Say(text string) error {
+	_, err := fmt.Fprintln(sink.W, text)
+	return err
+}
+
+// CommandSink runs a command for each announcement with the text as its last
+// argument, like spd-say for speech-dispatcher. Commands run one at a time in
+// the background so the game never waits on speech.
+type CommandSink struct {
+	name string
+	args []string
+
+	queue chan string
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewCommandSink constructs a sink that runs name with args and then the text
+func NewCommandSink(name string, args ...string) *CommandSink {
+	sink := &CommandSink{
+		name:  name,
+		args:  args,
+		queue: make(chan string, 64),
+		done:  make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+func (sink *CommandSink) run() {
+	defer close(sink.done)
+	for text := range sink.queue {
+		args := append(append([]string(nil), sink.args...), text)
+		if err := exec.Command(sink.name, args...).Run(); err != nil {
+			log.Println("Failed to run", sink.name, err)
+		}
+	}
+}
+
+// Say queues text to be run through the command
+func (sink *CommandSink) Say(text string) error {
+	select {
+	case sink.queue <- text:
+		return nil
+	default:
+		return errors.New("Too many announcements waiting for the command, dropping one")
+	}
+}
+
+// Close waits for queued announcements to finish, the sink can't be used after
+func (sink *CommandSink) Close() error {
+	sink.once.Do(func() { close(sink.queue) })
+	<-sink.done
+	return nil
+}
+
+// SetTextSink sets where announcements go, nil turns them off
+func (window *Window) SetTextSink(sink TextSink) {
+	window.textSink = sink
+}
+
+// TextSink returns where announcements go, nil when they're off
+func (window *Window) TextSink() TextSink {
+	return window.textSink
+}
+
+// Announce sends text to the text sink, if there is one. Apps announce what
+// a sighted player would pick up from the screen, like "Goblin attacks you".
+func (window *Window) Announce(text string) {
+	if window.textSink == nil || text == "" {
+		return
+	}
+	if err := window.textSink.Say(text); err != nil {
+		log.Println("Failed to announce", err)
+	}
+}
+
+// ReadRegion reads a rect of cells as text, row by row. Borders and block
+// graphics are left out and runs of spaces collapsed, so menus and status
+// panels read naturally.
+func (window *Window) ReadRegion(col int, row int, width int, height int) (string, error) {
+	if _, err := window.cellIndex(col, row); err != nil {
+		return "", err
+	}
+	if _, err := window.cellIndex(col+width-1, row+height-1); err != nil {
+		return "", err
+	}
+
+	rows := make([][]rune, 0, height)
+	for y := row; y < row+height; y++ {
+		line := make([]rune, 0, width)
+		for x := col; x < col+width; x++ {
+			glyph, _, _, _ := window.CellAt(x, y)
+			line = append(line, glyph)
+		}
+		rows = append(rows, line)
+	}
+	return linearize(rows), nil
+}
+
+// AnnounceRegion reads a rect of cells out through the text sink
+func (window *Window) AnnounceRegion(col int, row int, width int, height int) error {
+	text, err := window.ReadRegion(col, row, width, height)
+	if err != nil {
+		return err
+	}
+	window.Announce(text)
+	return nil
+}
+
+// decorative glyphs are drawing rather than text, box drawing, blocks and shades
+func decorative(glyph rune) bool {
+	return glyph >= 0x2500 && glyph <= 0x259f
+}
+
+// linearize joins rows of glyphs into lines of text, dropping empty rows
+func linearize(rows [][]rune) string {
+	var lines []string
+	for _, row := range rows {
+		var line strings.Builder
+		space := false
+		for _, glyph := range row {
+			if !unicode.IsPrint(glyph) || unicode.IsSpace(glyph) || decorative(glyph) {
+				space = true
+				continue
+			}
+			if space && line.Len() > 0 {
+				line.WriteByte(' ')
+			}
+			space = false
+			line.WriteRune(glyph)
+		}
+		if line.Len() > 0 {
+			lines = append(lines, line.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
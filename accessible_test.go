@@ -0,0 +1,62 @@
+package gterm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestLinearize(t *testing.T) {
+	rows := [][]rune{
+		[]rune("┌──────────┐"),
+		[]rune("│ Potion   │"),
+		[]rune("│          │"),
+		[]rune("│a  Sword ▒│"),
+		[]rune("└──────────┘"),
+	}
+	want := "Potion\na Sword"
+	if got := linearize(rows); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := WriterSink{W: &out}
+	sink.Say("Goblin attacks you")
+	sink.Say("You hit the goblin")
+
+	want := "Goblin attacks you\nYou hit the goblin\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
+
+func TestCommandSink(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("No shell to run commands with")
+	}
+	dir, err := ioutil.TempDir("", "gterm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spoken")
+
+	// sh puts the first argument after the script in $0
+	sink := NewCommandSink("sh", "-c", `printf '%s\n' "$0" >> "`+path+`"`)
+	sink.Say("first")
+	sink.Say("second")
+	sink.Close()
+
+	spoken, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(spoken) != "first\nsecond\n" {
+		t.Errorf("Expected announcements in order, got %q", spoken)
+	}
+}
@@ -0,0 +1,154 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// GlyphFrame is a single frame of a glyph animation, shown for Duration milliseconds
+type GlyphFrame struct {
+	Glyph    rune
+	FColor   sdl.Color
+	BColor   sdl.Color
+	Duration uint32
+}
+
+// GlyphAnimation is a sequence of frames a cell cycles through on its own. The
+// animation is timed from Start rather than from when it was put, so games that
+// redraw every cell each frame don't keep restarting it.
+type GlyphAnimation struct {
+	Frames []GlyphFrame
+	// Loop restarts the animation after the last frame, otherwise it holds the last frame
+	Loop  bool
+	Start uint32
+}
+
+// NewGlyphAnimation constructs an animation starting now
+func NewGlyphAnimation(loop bool, frames ...GlyphFrame) *GlyphAnimation {
+	return &GlyphAnimation{
+		Frames: frames,
+		Loop:   loop,
+		Start:  sdl.GetTicks(),
+	}
+}
+
+// Blink is a looping animation that alternates between glyph and nothing every interval milliseconds
+func Blink(glyph rune, fColor sdl.Color, bColor sdl.Color, interval uint32) *GlyphAnimation {
+	return NewGlyphAnimation(true,
+		GlyphFrame{Glyph: glyph, FColor: fColor, BColor: bColor, Duration: interval},
+		GlyphFrame{Glyph: ' ', FColor: fColor, BColor: NoColor, Duration: interval},
+	)
+}
+
+// duration is how long one pass through the frames takes
+func (animation *GlyphAnimation) duration() uint32 {
+	var total uint32
+	for _, frame := range animation.Frames {
+		total += frame.Duration
+	}
+	return total
+}
+
+// FrameAt is the index of the frame showing at ticks
+func (animation *GlyphAnimation) FrameAt(ticks uint32) int {
+	if len(animation.Frames) == 0 {
+		return -1
+	}
+	total := animation.duration()
+	if ticks < animation.Start || total == 0 {
+		return 0
+	}
+
+	elapsed := ticks - animation.Start
+	if animation.Loop {
+		elapsed %= total
+	} else if elapsed >= total {
+		return len(animation.Frames) - 1
+	}
+
+	for i, frame := range animation.Frames {
+		if elapsed < frame.Duration {
+			return i
+		}
+		elapsed -= frame.Duration
+	}
+	return len(animation.Frames) - 1
+}
+
+// nextChange is the tick at which the frame showing at ticks gives way to the
+// next one, ok is false when the animation has stopped changing
+func (animation *GlyphAnimation) nextChange(ticks uint32) (uint32, bool) {
+	total := animation.duration()
+	if len(animation.Frames) < 2 || total == 0 {
+		return 0, false
+	}
+	if ticks < animation.Start {
+		ticks = animation.Start
+	}
+
+	elapsed := ticks - animation.Start
+	if animation.Loop {
+		elapsed %= total
+	} else if elapsed >= total {
+		return 0, false
+	}
+
+	var end uint32
+	for _, frame := range animation.Frames {
+		end += frame.Duration
+		if elapsed < end {
+			break
+		}
+	}
+	return ticks + end - elapsed, true
+}
+
+// PutAnimation plays animation in a cell, drawn above whatever else was put
+// there. The window advances it on Refresh and only redraws the cell when the
+// frame changes.
+func (window *Window) PutAnimation(col int, row int, animation *GlyphAnimation) error {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return err
+	}
+	window.cells[index].animation = animation
+	window.cells[index].frame = animation.FrameAt(sdl.GetTicks())
+	return nil
+}
+
+// advanceAnimations moves every animated cell on to its current frame, blinks
+// decorations and works out when the next of them changes
+func (window *Window) advanceAnimations() {
+	ticks := sdl.GetTicks()
+	window.animatedCells = 0
+	for i := range window.cells {
+		next, animated := window.cells[i].advanceBlinking(ticks)
+		if animation := window.cells[i].animation; animation != nil {
+			window.cells[i].frame = animation.FrameAt(ticks)
+			if change, ok := animation.nextChange(ticks); ok && (!animated || change < next) {
+				next, animated = change, true
+			}
+		}
+		if !animated {
+			continue
+		}
+		if window.animatedCells == 0 || next < window.nextAnimationTick {
+			window.nextAnimationTick = next
+		}
+		window.animatedCells++
+	}
+}
+
+// Animating reports whether any cell held an animation or blinking decoration
+// that will still change, or any floating glyph was moving, at the last Refresh
+func (window *Window) Animating() bool {
+	return window.animatedCells > 0 || window.floatingMoving
+}
+
+func (window *Window) renderAnimationFrame(cell cell, destRect sdl.Rect) error {
+	if cell.animation == nil || cell.frame < 0 {
+		return nil
+	}
+	frame := cell.animation.Frames[cell.frame]
+	item := renderItem{Glyph: frame.Glyph, FColor: applyLight(frame.FColor, cell.light)}
+	return window.renderGlyph(item, applyLight(frame.BColor, cell.light), destRect)
+}
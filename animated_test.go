@@ -0,0 +1,112 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestFrameAt(t *testing.T) {
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	animation := &GlyphAnimation{
+		Frames: []GlyphFrame{
+			{Glyph: 'a', FColor: white, Duration: 100},
+			{Glyph: 'b', FColor: white, Duration: 50},
+		},
+		Start: 1000,
+	}
+
+	cases := []struct {
+		ticks uint32
+		loop  bool
+		frame int
+	}{
+		{ticks: 500, frame: 0},
+		{ticks: 1000, frame: 0},
+		{ticks: 1099, frame: 0},
+		{ticks: 1100, frame: 1},
+		{ticks: 1149, frame: 1},
+		{ticks: 1150, frame: 1},
+		{ticks: 5000, frame: 1},
+		{ticks: 1150, loop: true, frame: 0},
+		{ticks: 1260, loop: true, frame: 1},
+	}
+	for _, c := range cases {
+		animation.Loop = c.loop
+		if frame := animation.FrameAt(c.ticks); frame != c.frame {
+			t.Errorf("FrameAt(%v) loop %v = %v, expected %v", c.ticks, c.loop, frame, c.frame)
+		}
+	}
+}
+
+func TestFrameAtEmpty(t *testing.T) {
+	if frame := NewGlyphAnimation(true).FrameAt(0); frame != -1 {
+		t.Errorf("Expected -1 for an empty animation, got %v", frame)
+	}
+}
+
+func TestNextChange(t *testing.T) {
+	animation := &GlyphAnimation{
+		Frames: []GlyphFrame{{Glyph: 'a', Duration: 100}, {Glyph: 'b', Duration: 50}},
+		Start:  1000,
+	}
+
+	cases := []struct {
+		ticks uint32
+		loop  bool
+		next  uint32
+		ok    bool
+	}{
+		{ticks: 500, next: 1100, ok: true},
+		{ticks: 1000, next: 1100, ok: true},
+		{ticks: 1120, next: 1150, ok: true},
+		{ticks: 1150},
+		{ticks: 1150, loop: true, next: 1250, ok: true},
+		{ticks: 1260, loop: true, next: 1300, ok: true},
+	}
+	for _, c := range cases {
+		animation.Loop = c.loop
+		if next, ok := animation.nextChange(c.ticks); next != c.next || ok != c.ok {
+			t.Errorf("nextChange(%v) loop %v = %v %v, expected %v %v", c.ticks, c.loop, next, ok, c.next, c.ok)
+		}
+	}
+
+	still := &GlyphAnimation{Frames: []GlyphFrame{{Glyph: 'a', Duration: 100}}, Loop: true}
+	if _, ok := still.nextChange(50); ok {
+		t.Error("Expected a single frame never to change")
+	}
+}
+
+func TestTurnBasedSleepsUntilCellsChange(t *testing.T) {
+	window, destroy := newTestWindow(t, 4, 4)
+	defer destroy()
+
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+
+	// Blinking slowly enough that the next frame never comes during the test
+	window.PutAnimation(1, 1, Blink('*', white, NoColor, 60000))
+	source := &scriptedEvents{}
+	window.events = source
+	app := &loopApp{done: func(app *loopApp) bool { return len(source.waits) == 3 }}
+	window.Run(app, RunOptions{Mode: TurnBased, Step: 16})
+
+	if !window.Animating() {
+		t.Fatal("Expected the blinking cell to count as animating")
+	}
+	if app.renders != 1 {
+		t.Errorf("Expected no redraws before the next frame, got %v", app.renders)
+	}
+	for _, wait := range source.waits {
+		if wait != 250 {
+			t.Errorf("Expected waits capped at the idle timeout, got %v", source.waits)
+			break
+		}
+	}
+
+	window.ClearWindow()
+	window.PutAnimation(1, 1, Blink('*', white, NoColor, 100))
+	window.Refresh()
+	if wait := window.waitTimeout(app, RunOptions{Step: 16, IdleTimeout: 250}); wait < 1 || wait > 100 {
+		t.Errorf("Expected to wait until the next frame within 100ms, got %v", wait)
+	}
+}
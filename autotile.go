@@ -0,0 +1,128 @@
+package gterm
+
+// Mask reports whether a cell is part of the shape being joined, like "is a
+// wall". It is asked about cells just outside the region too. Report false
+// there to treat the border as open, so shapes end at the edge, or true to
+// treat it as solid, so walls along the border join into it like rock.
+type Mask func(col int, row int) bool
+
+// Neighbours is the set of cells around a cell that are set in a mask
+type Neighbours uint8
+
+// Directions of a cell's neighbours. The first four are the edges, which on
+// their own index a 16 glyph join table.
+const (
+	North Neighbours = 1 << iota
+	East
+	South
+	West
+	NorthEast
+	SouthEast
+	SouthWest
+	NorthWest
+)
+
+// Edges are the neighbours sharing a side with the cell
+const Edges = North | East | South | West
+
+var neighbourOffsets = []struct {
+	neighbour Neighbours
+	col, row  int
+}{
+	{North, 0, -1}, {East, 1, 0}, {South, 0, 1}, {West, -1, 0},
+	{NorthEast, 1, -1}, {SouthEast, 1, 1}, {SouthWest, -1, 1}, {NorthWest, -1, -1},
+}
+
+// Neighbours finds which of the eight cells around col, row are set
+func (mask Mask) Neighbours(col int, row int) Neighbours {
+	var neighbours Neighbours
+	for _, offset := range neighbourOffsets {
+		if mask(col+offset.col, row+offset.row) {
+			neighbours |= offset.neighbour
+		}
+	}
+	return neighbours
+}
+
+// Edge narrows the mask to set cells that touch an unset one, the faces of
+// solid areas. Dungeons carved out of solid rock want their walls joined along
+// the rooms, not through the rock.
+func (mask Mask) Edge() Mask {
+	return func(col int, row int) bool {
+		return mask(col, row) && mask.Neighbours(col, row) != 0xff
+	}
+}
+
+// Outline drops the joins into neighbours that are part of the same thick
+// area, a join is kept only where the cells either side of it aren't both set.
+// Walls two cells thick then get an outline rather than a lattice of crossings.
+func (neighbours Neighbours) Outline() Neighbours {
+	has := func(n Neighbours) bool { return neighbours&n == n }
+	outline := neighbours & Edges
+	if has(NorthEast | NorthWest) {
+		outline &^= North
+	}
+	if has(NorthEast | SouthEast) {
+		outline &^= East
+	}
+	if has(SouthEast | SouthWest) {
+		outline &^= South
+	}
+	if has(NorthWest | SouthWest) {
+		outline &^= West
+	}
+	return outline
+}
+
+// JoinTable picks the glyph for a cell from its neighbours. Tables for line
+// drawing have 16 glyphs, indexed by the edge bits. Tile fonts with a glyph
+// for every arrangement can have 256, indexed by all eight neighbours.
+type JoinTable struct {
+	Glyphs []rune
+	// Diagonals looks at all eight neighbours. With 16 glyphs the diagonals
+	// are used to outline thick areas, see Outline.
+	Diagonals bool
+}
+
+// Glyph returns the glyph for a cell with these neighbours
+func (table JoinTable) Glyph(neighbours Neighbours) rune {
+	switch {
+	case table.Diagonals && len(table.Glyphs) >= 256:
+		return table.Glyphs[neighbours]
+	case table.Diagonals:
+		return table.Glyphs[neighbours.Outline()]
+	default:
+		return table.Glyphs[neighbours&Edges]
+	}
+}
+
+// Join returns the glyph for the cell at col, row of mask
+func (table JoinTable) Join(mask Mask, col int, row int) rune {
+	return table.Glyph(mask.Neighbours(col, row))
+}
+
+// Region joins every cell of a columns x rows region, row by row. Cells not
+// set in the mask are 0.
+func (table JoinTable) Region(mask Mask, columns int, rows int) []rune {
+	glyphs := make([]rune, columns*rows)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			if mask(col, row) {
+				glyphs[col+row*columns] = table.Join(mask, col, row)
+			}
+		}
+	}
+	return glyphs
+}
+
+// SingleLines joins cells with CP437's single line box drawing
+var SingleLines = JoinTable{
+	Glyphs:    []rune("■│─└││┌├─┘─┴┐┤┬┼"),
+	Diagonals: true,
+}
+
+// DoubleLines joins cells with CP437's double line box drawing
+var DoubleLines = JoinTable{
+	Glyphs:    []rune("■║═╚║║╔╠═╝═╩╗╣╦╬"),
+	Diagonals: true,
+}
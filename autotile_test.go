@@ -0,0 +1,97 @@
+package gterm
+
+import (
+	"strings"
+	"testing"
+)
+
+// mapMask sets the cells of a text map that are '#', outside says what lies beyond it
+func mapMask(lines []string, outside bool) Mask {
+	return func(col int, row int) bool {
+		if row < 0 || row >= len(lines) || col < 0 || col >= len(lines[row]) {
+			return outside
+		}
+		return lines[row][col] == '#'
+	}
+}
+
+func joinedMap(table JoinTable, mask Mask, columns int, rows int) []string {
+	glyphs := table.Region(mask, columns, rows)
+	lines := make([]string, rows)
+	for row := range lines {
+		line := glyphs[row*columns : (row+1)*columns]
+		lines[row] = strings.Map(func(r rune) rune {
+			if r == 0 {
+				return ' '
+			}
+			return r
+		}, string(line))
+	}
+	return lines
+}
+
+func compareMaps(t *testing.T, got []string, want []string) {
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected\n%v\ngot\n%v", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestAutoTileOutline(t *testing.T) {
+	dungeon := []string{
+		"#######",
+		"#.....#",
+		"#.###.#",
+		"#.###.#",
+		"#.....#",
+		"#######",
+	}
+	compareMaps(t, joinedMap(SingleLines, mapMask(dungeon, false), 7, 6), []string{
+		"┌─────┐",
+		"│     │",
+		"│ ┌─┐ │",
+		"│ └─┘ │",
+		"│     │",
+		"└─────┘",
+	})
+}
+
+func TestAutoTileEdge(t *testing.T) {
+	rock := []string{
+		"#####",
+		"#####",
+		"##.##",
+		"#####",
+		"#####",
+	}
+	compareMaps(t, joinedMap(DoubleLines, mapMask(rock, true).Edge(), 5, 5), []string{
+		"     ",
+		" ╔═╗ ",
+		" ║ ║ ",
+		" ╚═╝ ",
+		"     ",
+	})
+}
+
+func TestAutoTileFourNeighbours(t *testing.T) {
+	thick := mapMask([]string{
+		"###",
+		"###",
+	}, false)
+	table := JoinTable{Glyphs: SingleLines.Glyphs}
+	compareMaps(t, joinedMap(table, thick, 3, 2), []string{
+		"┌┬┐",
+		"└┴┘",
+	})
+}
+
+func TestAutoTileFullTable(t *testing.T) {
+	glyphs := make([]rune, 256)
+	for i := range glyphs {
+		glyphs[i] = rune(i)
+	}
+	table := JoinTable{Glyphs: glyphs, Diagonals: true}
+	mask := mapMask([]string{"##", "##"}, false)
+	if got, want := table.Join(mask, 0, 0), rune(East|South|SouthEast); got != want {
+		t.Errorf("Expected index %v, got %v", want, got)
+	}
+}
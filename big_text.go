@@ -0,0 +1,315 @@
+package gterm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// BigFont draws characters as blocks of several cells
+type BigFont interface {
+	// Height is how many rows every character spans
+	Height() int
+	// Glyph returns the rows of cells for r, spaces are left transparent. ok is
+	// false when the font doesn't have the character.
+	Glyph(r rune) (rows [][]rune, ok bool)
+}
+
+// BigTextWidth is how many columns text spans in font, taking the widest line
+func BigTextWidth(text string, font BigFont) int {
+	widest := 0
+	for _, line := range strings.Split(text, "\n") {
+		width := 0
+		for _, r := range line {
+			if rows, ok := font.Glyph(r); ok && len(rows) > 0 {
+				width += len(rows[0])
+			}
+		}
+		if width > widest {
+			widest = width
+		}
+	}
+	return widest
+}
+
+// PutBigText writes text in a big font with its top left corner at (col, row).
+// Each line of text starts font.Height() rows below the last. Characters the
+// font doesn't have are skipped.
+func (window *Window) PutBigText(col int, row int, text string, font BigFont, fColor sdl.Color) error {
+	for lineNumber, line := range strings.Split(text, "\n") {
+		x := col
+		y := row + lineNumber*font.Height()
+		for _, r := range line {
+			rows, ok := font.Glyph(r)
+			if !ok {
+				continue
+			}
+			width := 0
+			for dy, glyphRow := range rows {
+				for dx, glyph := range glyphRow {
+					if glyph == ' ' {
+						continue
+					}
+					if err := window.PutRune(x+dx, y+dy, glyph, fColor, NoColor); err != nil {
+						return err
+					}
+				}
+				if len(glyphRow) > width {
+					width = len(glyphRow)
+				}
+			}
+			x += width
+		}
+	}
+	return nil
+}
+
+// FIGletFont is a FIGlet (.flf) font. Characters are laid out at their full
+// width, without kerning or smushing.
+type FIGletFont struct {
+	height int
+	glyphs map[rune][][]rune
+}
+
+// figletDeutsch are the characters that follow the ASCII ones in every FIGlet font
+var figletDeutsch = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// LoadFIGletFont reads a FIGlet font from path
+func LoadFIGletFont(path string) (*FIGletFont, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseFIGletFont(file)
+}
+
+// ParseFIGletFont reads a FIGlet font
+func ParseFIGletFont(r io.Reader) (*FIGletFont, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return nil, errors.New("Missing FIGlet header")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return nil, fmt.Errorf("Not a FIGlet font, header was %q", scanner.Text())
+	}
+	hardblank := []rune(header[0])[5]
+	height, err := strconv.Atoi(header[1])
+	if err != nil || height <= 0 {
+		return nil, fmt.Errorf("Invalid FIGlet height %q", header[1])
+	}
+	commentLines, err := strconv.Atoi(header[5])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid FIGlet comment line count %q", header[5])
+	}
+	for i := 0; i < commentLines; i++ {
+		scanner.Scan()
+	}
+
+	font := &FIGletFont{height: height, glyphs: make(map[rune][][]rune)}
+
+	readGlyph := func() ([][]rune, error) {
+		rows := make([][]rune, 0, height)
+		for i := 0; i < height; i++ {
+			if !scanner.Scan() {
+				return nil, io.ErrUnexpectedEOF
+			}
+			line := strings.TrimRight(scanner.Text(), " \r")
+			if line == "" {
+				return nil, fmt.Errorf("Empty FIGlet glyph row")
+			}
+			// Rows end with an end mark, doubled on the last row of a character
+			endMark := line[len(line)-1]
+			line = line[:len(line)-1]
+			if len(line) > 0 && line[len(line)-1] == endMark {
+				line = line[:len(line)-1]
+			}
+			rows = append(rows, []rune(strings.Replace(line, string(hardblank), " ", -1)))
+		}
+		return rows, nil
+	}
+
+	for r := rune(32); r <= 126; r++ {
+		rows, err := readGlyph()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read FIGlet character %q: %v", r, err)
+		}
+		font.glyphs[r] = rows
+	}
+	for _, r := range figletDeutsch {
+		rows, err := readGlyph()
+		if err != nil {
+			// Older fonts stop after ASCII
+			return font, nil
+		}
+		font.glyphs[r] = rows
+	}
+
+	// Anything else is tagged with its code on the line before
+	for scanner.Scan() {
+		tag := strings.Fields(scanner.Text())
+		if len(tag) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(tag[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid FIGlet character code %q", tag[0])
+		}
+		rows, err := readGlyph()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read FIGlet character %v: %v", code, err)
+		}
+		if code >= 0 {
+			font.glyphs[rune(code)] = rows
+		}
+	}
+
+	return font, scanner.Err()
+}
+
+func (font *FIGletFont) Height() int {
+	return font.height
+}
+
+func (font *FIGletFont) Glyph(r rune) ([][]rune, bool) {
+	rows, ok := font.glyphs[r]
+	return rows, ok
+}
+
+// ScaledFont blows glyphs from a CP437 sprite sheet up to columns x rows cells,
+// drawing them with quadrant blocks at twice the cell resolution.
+type ScaledFont struct {
+	columns int
+	rows    int
+
+	sheet         image.Image
+	glyphW        int
+	glyphH        int
+	spritesPerRow int
+	lookup        func(glyph rune) (int, bool)
+
+	cache map[rune][][]rune
+}
+
+// NewScaledFont loads the sheet at path, made of glyphW x glyphH sprites, and
+// scales each glyph to cover columns x rows cells.
+func NewScaledFont(path string, glyphW int, glyphH int, columns int, rows int) (*ScaledFont, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheet, err := png.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScaledFont{
+		columns:       columns,
+		rows:          rows,
+		sheet:         sheet,
+		glyphW:        glyphW,
+		glyphH:        glyphH,
+		spritesPerRow: sheet.Bounds().Dx() / glyphW,
+		lookup:        cp437Index,
+		cache:         make(map[rune][][]rune),
+	}, nil
+}
+
+// NewAtlasScaledFont scales the glyphs of a bitmap font to cover columns x rows cells
+func NewAtlasScaledFont(atlas *Atlas, columns int, rows int) *ScaledFont {
+	return &ScaledFont{
+		columns:       columns,
+		rows:          rows,
+		sheet:         atlas.Image,
+		glyphW:        atlas.CellWidth,
+		glyphH:        atlas.CellHeight,
+		spritesPerRow: atlas.Columns,
+		lookup:        atlas.Lookup,
+		cache:         make(map[rune][][]rune),
+	}
+}
+
+// ScaledFont scales the window's own font sheet or atlas, see NewScaledFont
+func (window *Window) ScaledFont(columns int, rows int) (*ScaledFont, error) {
+	if window.atlas != nil {
+		return NewAtlasScaledFont(window.atlas, columns, rows), nil
+	}
+	return NewScaledFont(window.fontPath, window.FontWPixel, window.FontHPixel, columns, rows)
+}
+
+func (font *ScaledFont) Height() int {
+	return font.rows
+}
+
+// lit reports whether at least a quarter of the sheet pixels in the rect are
+// lit, low enough that thin strokes survive being sampled
+func (font *ScaledFont) lit(x0, y0, x1, y1 int) bool {
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+	lit, total := 0, 0
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			pixel := font.sheet.At(x, y)
+			_, _, _, a := pixel.RGBA()
+			if color.GrayModel.Convert(pixel).(color.Gray).Y > 127 && a > 0 {
+				lit++
+			}
+			total++
+		}
+	}
+	return lit*4 >= total
+}
+
+func (font *ScaledFont) Glyph(r rune) ([][]rune, bool) {
+	if rows, ok := font.cache[r]; ok {
+		return rows, true
+	}
+
+	index, ok := font.lookup(r)
+	if !ok {
+		return nil, false
+	}
+	bounds := font.sheet.Bounds()
+	originX := bounds.Min.X + index%font.spritesPerRow*font.glyphW
+	originY := bounds.Min.Y + index/font.spritesPerRow*font.glyphH
+
+	across, down := font.columns*2, font.rows*2
+	rows := make([][]rune, font.rows)
+	for row := 0; row < font.rows; row++ {
+		rows[row] = make([]rune, font.columns)
+		for col := 0; col < font.columns; col++ {
+			var bits uint
+			for quad := uint(0); quad < 4; quad++ {
+				px, py := col*2+int(quad%2), row*2+int(quad/2)
+				x0 := originX + px*font.glyphW/across
+				x1 := originX + (px+1)*font.glyphW/across
+				y0 := originY + py*font.glyphH/down
+				y1 := originY + (py+1)*font.glyphH/down
+				if font.lit(x0, y0, x1, y1) {
+					bits |= 1 << quad
+				}
+			}
+			rows[row][col] = quadrantGlyphs[bits]
+		}
+	}
+
+	font.cache[r] = rows
+	return rows, true
+}
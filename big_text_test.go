@@ -0,0 +1,60 @@
+package gterm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testFIGletFont builds a two row font where every character is drawn as itself
+// over a row of dashes, with a code tagged smiley at the end.
+func testFIGletFont() string {
+	var font strings.Builder
+	font.WriteString("flf2a$ 2 1 4 -1 1\n")
+	font.WriteString("A test font\n")
+	for r := 32; r <= 126; r++ {
+		glyph := string(rune(r))
+		if r == ' ' {
+			glyph = "$"
+		}
+		fmt.Fprintf(&font, "%v#\n-#\n", glyph)
+	}
+	for range figletDeutsch {
+		font.WriteString("?#\n?##\n")
+	}
+	font.WriteString("0x263A  SMILING FACE\n:)#\n--##\n")
+	return font.String()
+}
+
+func TestParseFIGletFont(t *testing.T) {
+	font, err := ParseFIGletFont(strings.NewReader(testFIGletFont()))
+	if err != nil {
+		t.Fatalf("Failed to parse font: %v", err)
+	}
+
+	if font.Height() != 2 {
+		t.Errorf("Expected height 2 but got %v", font.Height())
+	}
+
+	expected := map[rune][]string{
+		'A': {"A", "-"},
+		' ': {" ", "-"},
+		'☺': {":)", "--"},
+	}
+	for r, rows := range expected {
+		glyph, ok := font.Glyph(r)
+		if !ok {
+			t.Errorf("Expected font to have %q", r)
+			continue
+		}
+		for i, row := range rows {
+			if string(glyph[i]) != row {
+				t.Errorf("Glyph %q row %v: expected %q but got %q", r, i, row, string(glyph[i]))
+			}
+		}
+	}
+
+	if width := BigTextWidth("AA\n☺", font); width != 2 {
+		t.Errorf("Expected width 2 but got %v", width)
+	}
+}
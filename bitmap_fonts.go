@@ -0,0 +1,456 @@
+package gterm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Atlas is a bitmap font packed into a sheet of equally sized cells, along
+// with the cell each rune is drawn from. Set pixels are opaque white so glyphs
+// take on the colour they're drawn with.
+type Atlas struct {
+	Image      *image.RGBA
+	CellWidth  int
+	CellHeight int
+	// Columns is how many cells there are across the sheet
+	Columns int
+	Glyphs  map[rune]int
+}
+
+// Lookup finds the cell a rune is drawn from
+func (atlas *Atlas) Lookup(glyph rune) (int, bool) {
+	index, ok := atlas.Glyphs[glyph]
+	return index, ok
+}
+
+// CellRect is the rect of a cell in the sheet
+func (atlas *Atlas) CellRect(index int) image.Rectangle {
+	x := index % atlas.Columns * atlas.CellWidth
+	y := index / atlas.Columns * atlas.CellHeight
+	return image.Rect(x, y, x+atlas.CellWidth, y+atlas.CellHeight)
+}
+
+// bitmap is one glyph as rows of set pixels, before packing
+type bitmap struct {
+	runes  []rune
+	pixels [][]bool
+	// x and y place the bitmap within its cell
+	x, y int
+}
+
+// newAtlas packs bitmaps into a roughly square sheet of cellW x cellH cells.
+// Pixels falling outside a cell are clipped. Runes claimed by an earlier
+// glyph keep it.
+func newAtlas(cellW int, cellH int, bitmaps []bitmap) (*Atlas, error) {
+	if cellW <= 0 || cellH <= 0 {
+		return nil, fmt.Errorf("Invalid glyph size %vx%v", cellW, cellH)
+	}
+	if len(bitmaps) == 0 {
+		return nil, errors.New("Font has no glyphs")
+	}
+
+	columns := int(math.Ceil(math.Sqrt(float64(len(bitmaps)))))
+	rows := (len(bitmaps) + columns - 1) / columns
+	atlas := &Atlas{
+		Image:      image.NewRGBA(image.Rect(0, 0, columns*cellW, rows*cellH)),
+		CellWidth:  cellW,
+		CellHeight: cellH,
+		Columns:    columns,
+		Glyphs:     make(map[rune]int),
+	}
+
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	for index, glyph := range bitmaps {
+		cell := atlas.CellRect(index)
+		for y, row := range glyph.pixels {
+			for x, set := range row {
+				px, py := glyph.x+x, glyph.y+y
+				if set && px >= 0 && px < cellW && py >= 0 && py < cellH {
+					atlas.Image.SetRGBA(cell.Min.X+px, cell.Min.Y+py, white)
+				}
+			}
+		}
+		for _, r := range glyph.runes {
+			if _, taken := atlas.Glyphs[r]; !taken {
+				atlas.Glyphs[r] = index
+			}
+		}
+	}
+	return atlas, nil
+}
+
+// LoadAtlas loads a PSF or BDF font, PSF fonts may be gzipped like the Linux
+// console fonts. Tileset descriptors ending .json are loaded with their sheet.
+func LoadAtlas(path string) (*Atlas, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return LoadTileset(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadAtlas(file)
+}
+
+// ReadAtlas reads a PSF or BDF font, telling them apart by their first bytes
+func ReadAtlas(r io.Reader) (*Atlas, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		unzipped, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = ioutil.ReadAll(unzipped); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(data, psf1Magic), bytes.HasPrefix(data, psf2Magic):
+		return ParsePSF(data)
+	case bytes.HasPrefix(data, []byte("STARTFONT")):
+		return ParseBDF(bytes.NewReader(data))
+	}
+	return nil, errors.New("Unknown font format, expected PSF or BDF")
+}
+
+var (
+	psf1Magic = []byte{0x36, 0x04}
+	psf2Magic = []byte{0x72, 0xb5, 0x4a, 0x86}
+)
+
+const (
+	psf1Mode512    = 0x01
+	psf1ModeTable  = 0x02
+	psf1ModeSeq    = 0x04
+	psf2HasTable   = 0x01
+	psf1Separator  = 0xffff
+	psf1StartSeq   = 0xfffe
+	psf2Separator  = 0xff
+	psf2StartSeq   = 0xfe
+	psf2HeaderSize = 32
+)
+
+// ParsePSF reads a version 1 or 2 PC Screen Font. Runes come from the font's
+// unicode table, fonts without one are taken to be in CP437 order.
+func ParsePSF(data []byte) (*Atlas, error) {
+	var count, width, height, charSize int
+	var glyphData, table []byte
+	psf2 := false
+
+	switch {
+	case bytes.HasPrefix(data, psf1Magic):
+		if len(data) < 4 {
+			return nil, errors.New("PSF header is cut short")
+		}
+		mode := data[2]
+		width, height, charSize = 8, int(data[3]), int(data[3])
+		count = 256
+		if mode&psf1Mode512 != 0 {
+			count = 512
+		}
+		end := 4 + count*charSize
+		if len(data) < end {
+			return nil, errors.New("PSF glyphs are cut short")
+		}
+		glyphData = data[4:end]
+		if mode&(psf1ModeTable|psf1ModeSeq) != 0 {
+			table = data[end:]
+		}
+	case bytes.HasPrefix(data, psf2Magic):
+		if len(data) < psf2HeaderSize {
+			return nil, errors.New("PSF2 header is cut short")
+		}
+		header := func(field int) int {
+			return int(binary.LittleEndian.Uint32(data[field*4:]))
+		}
+		headerSize, flags := header(2), header(3)
+		count, charSize, height, width = header(4), header(5), header(6), header(7)
+		// The header is checked against the data before anything is multiplied,
+		// so corrupt sizes can't overflow
+		if charSize <= 0 || width > 8*charSize || height > charSize || (width+7)/8*height > charSize {
+			return nil, fmt.Errorf("PSF2 glyphs of %vx%v don't fit in %v bytes", width, height, charSize)
+		}
+		if headerSize < psf2HeaderSize || headerSize > len(data) || count > (len(data)-headerSize)/charSize {
+			return nil, errors.New("PSF2 glyphs are cut short")
+		}
+		end := headerSize + count*charSize
+		glyphData = data[headerSize:end]
+		if flags&psf2HasTable != 0 {
+			table = data[end:]
+		}
+		psf2 = true
+	default:
+		return nil, errors.New("Not a PSF font")
+	}
+
+	bytesPerRow := (width + 7) / 8
+	bitmaps := make([]bitmap, count)
+	for i := range bitmaps {
+		glyph := glyphData[i*charSize : (i+1)*charSize]
+		pixels := make([][]bool, height)
+		for y := range pixels {
+			pixels[y] = make([]bool, width)
+			for x := range pixels[y] {
+				pixels[y][x] = glyph[y*bytesPerRow+x/8]&(0x80>>uint(x%8)) != 0
+			}
+		}
+		bitmaps[i].pixels = pixels
+	}
+
+	switch {
+	case table == nil:
+		for i := 0; i < count && i < 256; i++ {
+			bitmaps[i].runes = []rune{CP437.DecodeByte(byte(i))}
+		}
+	case psf2:
+		psf2Runes(table, bitmaps)
+	default:
+		psf1Runes(table, bitmaps)
+	}
+
+	return newAtlas(width, height, bitmaps)
+}
+
+// psf1Runes reads a PSF1 unicode table, a list of UCS-2 runes per glyph.
+// Combining sequences after 0xFFFE can't be drawn from one cell and are skipped.
+func psf1Runes(table []byte, bitmaps []bitmap) {
+	glyph, inSequence := 0, false
+	for i := 0; i+1 < len(table) && glyph < len(bitmaps); i += 2 {
+		value := binary.LittleEndian.Uint16(table[i:])
+		switch {
+		case value == psf1Separator:
+			glyph++
+			inSequence = false
+		case value == psf1StartSeq:
+			inSequence = true
+		case !inSequence:
+			bitmaps[glyph].runes = append(bitmaps[glyph].runes, rune(value))
+		}
+	}
+}
+
+// psf2Runes reads a PSF2 unicode table, UTF-8 runes for each glyph
+func psf2Runes(table []byte, bitmaps []bitmap) {
+	glyph, inSequence := 0, false
+	for i := 0; i < len(table) && glyph < len(bitmaps); {
+		switch table[i] {
+		case psf2Separator:
+			glyph++
+			inSequence = false
+			i++
+			continue
+		case psf2StartSeq:
+			inSequence = true
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(table[i:])
+		if !inSequence && r != utf8.RuneError {
+			bitmaps[glyph].runes = append(bitmaps[glyph].runes, r)
+		}
+		i += size
+	}
+}
+
+// maxBDFGlyphSize bounds the sides of BDF bounding boxes, anything bigger is
+// taken to be corrupt rather than allocated
+const maxBDFGlyphSize = 512
+
+// ParseBDF reads a Glyph Bitmap Distribution Format font. Each glyph's
+// ENCODING is its rune, fonts are expected to be ISO10646 or ISO8859-1 as
+// nearly all are. Glyphs are placed in cells the size of the font's bounding
+// box, lined up on its baseline.
+func ParseBDF(r io.Reader) (*Atlas, error) {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+
+	var cellW, cellH, fontX, fontY int
+	haveBox := false
+	var bitmaps []bitmap
+
+	var glyph *bitmap
+	var glyphW, glyphH, glyphX, glyphY int
+	encoding := -1
+	inBitmap := false
+
+	numbers := func(fields []string, want int) ([]int, error) {
+		if len(fields) < want+1 {
+			return nil, fmt.Errorf("Line %v: %v needs %v numbers", lineNumber, fields[0], want)
+		}
+		values := make([]int, want)
+		for i := range values {
+			value, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("Line %v: %v", lineNumber, err)
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inBitmap && fields[0] != "ENDCHAR" {
+			row, err := hex.DecodeString(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("Line %v: bad bitmap row %q", lineNumber, fields[0])
+			}
+			pixels := make([]bool, glyphW)
+			for x := range pixels {
+				pixels[x] = x/8 < len(row) && row[x/8]&(0x80>>uint(x%8)) != 0
+			}
+			glyph.pixels = append(glyph.pixels, pixels)
+			continue
+		}
+
+		switch fields[0] {
+		case "FONTBOUNDINGBOX":
+			values, err := numbers(fields, 4)
+			if err != nil {
+				return nil, err
+			}
+			if values[0] <= 0 || values[1] <= 0 || values[0] > maxBDFGlyphSize || values[1] > maxBDFGlyphSize {
+				return nil, fmt.Errorf("Line %v: bad font bounding box %vx%v", lineNumber, values[0], values[1])
+			}
+			cellW, cellH, fontX, fontY = values[0], values[1], values[2], values[3]
+			haveBox = true
+		case "STARTCHAR":
+			if !haveBox {
+				return nil, fmt.Errorf("Line %v: glyph before FONTBOUNDINGBOX", lineNumber)
+			}
+			glyph = &bitmap{}
+			glyphW, glyphH, glyphX, glyphY = cellW, cellH, fontX, fontY
+			encoding = -1
+		case "ENCODING":
+			values, err := numbers(fields, 1)
+			if err != nil {
+				return nil, err
+			}
+			encoding = values[0]
+		case "BBX":
+			values, err := numbers(fields, 4)
+			if err != nil {
+				return nil, err
+			}
+			// Blank glyphs, like space, can be empty
+			if values[0] < 0 || values[1] < 0 || values[0] > maxBDFGlyphSize || values[1] > maxBDFGlyphSize {
+				return nil, fmt.Errorf("Line %v: bad glyph bounding box %vx%v", lineNumber, values[0], values[1])
+			}
+			glyphW, glyphH, glyphX, glyphY = values[0], values[1], values[2], values[3]
+		case "BITMAP":
+			if glyph == nil {
+				return nil, fmt.Errorf("Line %v: BITMAP outside a glyph", lineNumber)
+			}
+			inBitmap = true
+		case "ENDCHAR":
+			if glyph == nil {
+				return nil, fmt.Errorf("Line %v: ENDCHAR outside a glyph", lineNumber)
+			}
+			// The baseline sits fontY above the bottom of the cell
+			glyph.x = glyphX - fontX
+			glyph.y = cellH + fontY - glyphY - glyphH
+			if encoding >= 0 {
+				glyph.runes = []rune{rune(encoding)}
+				bitmaps = append(bitmaps, *glyph)
+			}
+			glyph, inBitmap = nil, false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !haveBox {
+		return nil, errors.New("BDF font has no FONTBOUNDINGBOX")
+	}
+
+	return newAtlas(cellW, cellH, bitmaps)
+}
+
+// UseAtlas draws glyphs from a bitmap font rather than the window's CP437
+// sprite sheet, cells take the font's size. Before Init the atlas is loaded
+// when the window is created.
+func (window *Window) UseAtlas(atlas *Atlas) error {
+	window.atlas = atlas
+	if window.SdlRenderer == nil {
+		window.FontWPixel, window.FontHPixel = atlas.CellWidth, atlas.CellHeight
+		window.DisplayWPixel, window.DisplayHPixel = atlas.CellWidth, atlas.CellHeight
+		window.WidthPixel = window.Columns * atlas.CellWidth
+		window.HeightPixel = window.Rows * atlas.CellHeight
+		return nil
+	}
+
+	texture, err := window.loadFont(atlas.CellWidth)
+	if err != nil {
+		return err
+	}
+	window.useFontSheet(texture, atlas.CellWidth, atlas.CellHeight)
+	return nil
+}
+
+// Atlas returns the bitmap font in use, nil when drawing from a sprite sheet
+func (window *Window) Atlas() *Atlas {
+	return window.atlas
+}
+
+// atlasTexture uploads the atlas sheet to the renderer
+func (window *Window) atlasTexture(atlas *Atlas) (*sdl.Texture, error) {
+	bounds := atlas.Image.Bounds()
+	texture, err := window.SdlRenderer.CreateTexture(uint32(sdl.PIXELFORMAT_RGBA32), sdl.TEXTUREACCESS_STATIC, bounds.Dx(), bounds.Dy())
+	if err != nil {
+		return nil, err
+	}
+	if err := texture.Update(nil, atlas.Image.Pix, atlas.Image.Stride); err != nil {
+		texture.Destroy()
+		return nil, err
+	}
+	if err := texture.SetBlendMode(sdl.BLENDMODE_BLEND); err != nil {
+		texture.Destroy()
+		return nil, err
+	}
+	window.spritesPerRow = atlas.Columns
+	return texture, nil
+}
+
+// glyphIndex finds the sprite a rune is drawn from, in the atlas if there is
+// one or else the CP437 sheet
+func (window *Window) glyphIndex(glyph rune) (int, bool) {
+	if window.atlas != nil {
+		return window.atlas.Lookup(glyph)
+	}
+	return cp437Index(glyph)
+}
+
+// cp437Index finds the sprite a rune is drawn from in a CP437 sheet
+func cp437Index(glyph rune) (int, bool) {
+	index, ok := CP437.EncodeRune(glyph)
+	return int(index), ok
+}
@@ -0,0 +1,198 @@
+package gterm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// atlasGlyph draws a glyph of the atlas as rows of '#' and '.'
+func atlasGlyph(t *testing.T, atlas *Atlas, glyph rune) string {
+	index, ok := atlas.Lookup(glyph)
+	if !ok {
+		t.Fatalf("Expected %q in the atlas", glyph)
+	}
+	cell := atlas.CellRect(index)
+	var rows []string
+	for y := cell.Min.Y; y < cell.Max.Y; y++ {
+		var row strings.Builder
+		for x := cell.Min.X; x < cell.Max.X; x++ {
+			if atlas.Image.RGBAAt(x, y).A > 0 {
+				row.WriteByte('#')
+			} else {
+				row.WriteByte('.')
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return strings.Join(rows, "\n")
+}
+
+func psf2Font(width int, height int, glyphs [][]byte, table []byte) []byte {
+	var font bytes.Buffer
+	font.Write(psf2Magic)
+	flags := 0
+	if table != nil {
+		flags = psf2HasTable
+	}
+	charSize := (width + 7) / 8 * height
+	for _, field := range []int{0, psf2HeaderSize, flags, len(glyphs), charSize, height, width} {
+		binary.Write(&font, binary.LittleEndian, uint32(field))
+	}
+	for _, glyph := range glyphs {
+		font.Write(glyph)
+	}
+	font.Write(table)
+	return font.Bytes()
+}
+
+func TestParsePSF2(t *testing.T) {
+	// 10 pixels wide so rows take two bytes
+	glyphs := [][]byte{
+		{0xff, 0xc0, 0x80, 0x40},
+		{0x0c, 0x00, 0x30, 0x00},
+	}
+	table := []byte("A\xffé→\xfeé\xff")
+	atlas, err := ParsePSF(psf2Font(10, 2, glyphs, table))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if atlas.CellWidth != 10 || atlas.CellHeight != 2 {
+		t.Errorf("Expected 10x2 cells, got %vx%v", atlas.CellWidth, atlas.CellHeight)
+	}
+	if got, want := atlasGlyph(t, atlas, 'A'), "##########\n#........#"; got != want {
+		t.Errorf("Expected A as\n%v\ngot\n%v", want, got)
+	}
+	if got, want := atlasGlyph(t, atlas, '→'), "....##....\n..##......"; got != want {
+		t.Errorf("Expected → as\n%v\ngot\n%v", want, got)
+	}
+	if a, _ := atlas.Lookup('é'); a != 1 {
+		t.Errorf("Expected é on the second glyph")
+	}
+	if _, ok := atlas.Lookup('B'); ok {
+		t.Errorf("Expected B to be missing")
+	}
+}
+
+func TestParsePSF1WithoutTable(t *testing.T) {
+	font := []byte{psf1Magic[0], psf1Magic[1], 0, 1}
+	glyphs := make([]byte, 256)
+	glyphs[0xdb] = 0xff // CP437's full block
+	font = append(font, glyphs...)
+
+	atlas, err := ParsePSF(font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atlasGlyph(t, atlas, '█'); got != "########" {
+		t.Errorf("Expected glyphs in CP437 order, █ is %q", got)
+	}
+}
+
+func TestParsePSF2CorruptHeader(t *testing.T) {
+	corrupt := func(count uint32, charSize uint32, height uint32, width uint32) []byte {
+		font := psf2Font(8, 1, [][]byte{{0xff}}, nil)
+		for i, field := range []uint32{count, charSize, height, width} {
+			binary.LittleEndian.PutUint32(font[16+4*i:], field)
+		}
+		return font
+	}
+	fonts := map[string][]byte{
+		"huge count":       corrupt(0xffffffff, 1, 1, 8),
+		"huge glyphs":      corrupt(1, 0xffffffff, 1, 8),
+		"huge everything":  corrupt(0xffffffff, 0xffffffff, 0xffffffff, 0xffffffff),
+		"empty glyphs":     corrupt(0xffffffff, 0, 0, 0),
+		"glyphs too large": corrupt(1, 1, 2, 8),
+	}
+	for name, font := range fonts {
+		if _, err := ParsePSF(font); err == nil {
+			t.Errorf("Expected an error for %v", name)
+		}
+	}
+}
+
+const testBDF = `STARTFONT 2.1
+FONT -test-fixed-medium-r-normal--4-40-75-75-c-40-iso10646-1
+SIZE 4 75 75
+FONTBOUNDINGBOX 4 5 0 -1
+CHARS 2
+STARTCHAR dot
+ENCODING 46
+BBX 1 1 1 0
+BITMAP
+80
+ENDCHAR
+STARTCHAR g
+ENCODING 103
+BBX 3 3 0 -1
+BITMAP
+E0
+20
+C0
+ENDCHAR
+STARTCHAR unencoded
+ENCODING -1
+BBX 4 5 0 -1
+BITMAP
+F0
+F0
+F0
+F0
+F0
+ENDCHAR
+ENDFONT
+`
+
+func TestParseBDF(t *testing.T) {
+	atlas, err := ParseBDF(strings.NewReader(testBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atlas.Glyphs) != 2 {
+		t.Errorf("Expected the unencoded glyph to be skipped, got %v glyphs", len(atlas.Glyphs))
+	}
+	// The baseline is one row up from the bottom of the 5 row cell
+	if got, want := atlasGlyph(t, atlas, '.'), "....\n....\n....\n.#..\n...."; got != want {
+		t.Errorf("Expected . on the baseline\n%v\ngot\n%v", want, got)
+	}
+	if got, want := atlasGlyph(t, atlas, 'g'), "....\n....\n###.\n..#.\n##.."; got != want {
+		t.Errorf("Expected g to descend below the baseline\n%v\ngot\n%v", want, got)
+	}
+}
+
+func TestParseBDFBadBoundingBox(t *testing.T) {
+	fonts := map[string]string{
+		"negative glyph width":  strings.Replace(testBDF, "BBX 1 1 1 0", "BBX -1 1 1 0", 1),
+		"negative glyph height": strings.Replace(testBDF, "BBX 1 1 1 0", "BBX 1 -1 1 0", 1),
+		"huge glyph":            strings.Replace(testBDF, "BBX 1 1 1 0", "BBX 1000000000 1 1 0", 1),
+		"negative font width":   strings.Replace(testBDF, "FONTBOUNDINGBOX 4 5", "FONTBOUNDINGBOX -4 5", 1),
+		"empty font":            strings.Replace(testBDF, "FONTBOUNDINGBOX 4 5", "FONTBOUNDINGBOX 4 0", 1),
+		"huge font":             strings.Replace(testBDF, "FONTBOUNDINGBOX 4 5", "FONTBOUNDINGBOX 4 1000000000", 1),
+	}
+	for name, font := range fonts {
+		if _, err := ParseBDF(strings.NewReader(font)); err == nil || !strings.HasPrefix(err.Error(), "Line ") {
+			t.Errorf("Expected a line numbered error for %v, got %v", name, err)
+		}
+	}
+}
+
+func TestReadAtlasGzip(t *testing.T) {
+	var zipped bytes.Buffer
+	writer := gzip.NewWriter(&zipped)
+	writer.Write(psf2Font(8, 1, [][]byte{{0x81}}, []byte("x\xff")))
+	writer.Close()
+
+	atlas, err := ReadAtlas(&zipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atlasGlyph(t, atlas, 'x'); got != "#......#" {
+		t.Errorf("Expected the gzipped glyph, got %q", got)
+	}
+	if _, err := ReadAtlas(strings.NewReader("not a font")); err == nil {
+		t.Errorf("Expected an error reading something that isn't a font")
+	}
+}
@@ -0,0 +1,244 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// CanvasMode selects how many pixels a canvas packs into each cell
+type CanvasMode int
+
+const (
+	// HalfBlocks packs 1x2 pixels per cell using ▀ ▄ █
+	HalfBlocks CanvasMode = iota
+	// QuadrantBlocks packs 2x2 pixels per cell using the quadrant block elements
+	QuadrantBlocks
+	// Braille packs 2x4 pixels per cell using braille patterns
+	Braille
+)
+
+// CellPixels is how many canvas pixels fit across and down a single cell
+func (mode CanvasMode) CellPixels() (w int, h int) {
+	switch mode {
+	case QuadrantBlocks:
+		return 2, 2
+	case Braille:
+		return 2, 4
+	}
+	return 1, 2
+}
+
+// halfBlockGlyphs is indexed by bits top=1, bottom=2
+var halfBlockGlyphs = []rune{' ', '▀', '▄', '█'}
+
+// quadrantGlyphs is indexed by bits top left=1, top right=2, bottom left=4, bottom right=8
+var quadrantGlyphs = []rune{' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█'}
+
+// brailleDots maps a pixel inside a braille cell, indexed y*2+x, to its dot bit
+var brailleDots = []uint{0x01, 0x08, 0x02, 0x10, 0x04, 0x20, 0x40, 0x80}
+
+func (mode CanvasMode) glyph(bits uint) rune {
+	switch mode {
+	case QuadrantBlocks:
+		return quadrantGlyphs[bits]
+	case Braille:
+		var dots uint
+		for i, dot := range brailleDots {
+			if bits&(1<<uint(i)) != 0 {
+				dots |= dot
+			}
+		}
+		return rune(0x2800 + dots)
+	}
+	return halfBlockGlyphs[bits]
+}
+
+// Canvas is a pixel grid drawn into the window at a finer resolution than
+// cells. Each cell's pixels are packed into a glyph with a foreground and a
+// background colour, so a cell can only show two colours at once.
+type Canvas struct {
+	Mode    CanvasMode
+	Columns int
+	Rows    int
+
+	width  int
+	height int
+	pixels []sdl.Color
+}
+
+// NewCanvas constructs a canvas covering columns x rows cells
+func NewCanvas(columns int, rows int, mode CanvasMode) *Canvas {
+	cellW, cellH := mode.CellPixels()
+	width, height := columns*cellW, rows*cellH
+	return &Canvas{
+		Mode:    mode,
+		Columns: columns,
+		Rows:    rows,
+		width:   width,
+		height:  height,
+		pixels:  make([]sdl.Color, width*height),
+	}
+}
+
+// Size is the canvas resolution in pixels
+func (canvas *Canvas) Size() (w int, h int) {
+	return canvas.width, canvas.height
+}
+
+// Set colours the pixel at (x, y), pixels outside the canvas are ignored
+func (canvas *Canvas) Set(x int, y int, color sdl.Color) {
+	if x < 0 || x >= canvas.width || y < 0 || y >= canvas.height {
+		return
+	}
+	canvas.pixels[x+y*canvas.width] = color
+}
+
+// At returns the colour of the pixel at (x, y), NoColor when unset
+func (canvas *Canvas) At(x int, y int) sdl.Color {
+	if x < 0 || x >= canvas.width || y < 0 || y >= canvas.height {
+		return NoColor
+	}
+	return canvas.pixels[x+y*canvas.width]
+}
+
+// Clear unsets every pixel
+func (canvas *Canvas) Clear() {
+	for i := range canvas.pixels {
+		canvas.pixels[i] = NoColor
+	}
+}
+
+func colorDistance(a sdl.Color, b sdl.Color) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// Cell packs the pixels of a cell into a glyph and colours. The most common
+// colour becomes the foreground and the next most common the background, other
+// colours snap to whichever of the two is closer. ok is false when every pixel
+// in the cell is unset.
+func (canvas *Canvas) Cell(col int, row int) (glyph rune, fColor sdl.Color, bColor sdl.Color, ok bool) {
+	cellW, cellH := canvas.Mode.CellPixels()
+	originX, originY := col*cellW, row*cellH
+
+	var colors []sdl.Color
+	var counts []int
+	for y := 0; y < cellH; y++ {
+		for x := 0; x < cellW; x++ {
+			pixel := canvas.At(originX+x, originY+y)
+			if pixel == NoColor {
+				continue
+			}
+			found := false
+			for i, c := range colors {
+				if c == pixel {
+					counts[i]++
+					found = true
+					break
+				}
+			}
+			if !found {
+				colors = append(colors, pixel)
+				counts = append(counts, 1)
+			}
+		}
+	}
+	if len(colors) == 0 {
+		return ' ', NoColor, NoColor, false
+	}
+
+	// First seen wins ties so the result doesn't depend on anything but the pixels
+	first, second := 0, -1
+	for i := 1; i < len(colors); i++ {
+		if counts[i] > counts[first] {
+			first, second = i, first
+		} else if second < 0 || counts[i] > counts[second] {
+			second = i
+		}
+	}
+	fColor, bColor = colors[first], NoColor
+	if second >= 0 {
+		bColor = colors[second]
+	}
+
+	var bits uint
+	for y := 0; y < cellH; y++ {
+		for x := 0; x < cellW; x++ {
+			pixel := canvas.At(originX+x, originY+y)
+			if pixel == NoColor {
+				continue
+			}
+			if bColor == NoColor || colorDistance(pixel, fColor) <= colorDistance(pixel, bColor) {
+				bits |= 1 << uint(y*cellW+x)
+			}
+		}
+	}
+
+	return canvas.Mode.glyph(bits), fColor, bColor, true
+}
+
+// Draw puts the canvas into the window with its top left corner at (col, row).
+// Cells without any pixels set are left alone.
+func (canvas *Canvas) Draw(window *Window, col int, row int) error {
+	for y := 0; y < canvas.Rows; y++ {
+		for x := 0; x < canvas.Columns; x++ {
+			glyph, fColor, bColor, ok := canvas.Cell(x, y)
+			if !ok {
+				continue
+			}
+			if err := window.PutRune(col+x, row+y, glyph, fColor, bColor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// blockRects breaks block element and braille glyphs into rectangles inside
+// dest so they can be drawn when the font sheet doesn't have them. ok is false
+// for any other glyph.
+func blockRects(glyph rune, dest sdl.Rect) ([]sdl.Rect, bool) {
+	var cellW, cellH int32
+	var bits uint
+	switch {
+	case glyph >= 0x2800 && glyph <= 0x28FF:
+		cellW, cellH = 2, 4
+		dots := uint(glyph - 0x2800)
+		for i, dot := range brailleDots {
+			if dots&dot != 0 {
+				bits |= 1 << uint(i)
+			}
+		}
+	default:
+		found := false
+		for i, g := range quadrantGlyphs {
+			if g == glyph {
+				cellW, cellH, bits, found = 2, 2, uint(i), true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	var rects []sdl.Rect
+	for y := int32(0); y < cellH; y++ {
+		for x := int32(0); x < cellW; x++ {
+			if bits&(1<<uint(y*cellW+x)) == 0 {
+				continue
+			}
+			x0, x1 := dest.W*x/cellW, dest.W*(x+1)/cellW
+			y0, y1 := dest.H*y/cellH, dest.H*(y+1)/cellH
+			rect := sdl.Rect{X: dest.X + x0, Y: dest.Y + y0, W: x1 - x0, H: y1 - y0}
+			if cellH == 4 {
+				// Shrink braille dots so they read as dots rather than blocks
+				rect.X += rect.W / 4
+				rect.Y += rect.H / 4
+				rect.W -= rect.W / 2
+				rect.H -= rect.H / 2
+			}
+			rects = append(rects, rect)
+		}
+	}
+	return rects, true
+}
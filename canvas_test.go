@@ -0,0 +1,67 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var (
+	testRed  = sdl.Color{R: 255, A: 255}
+	testBlue = sdl.Color{B: 255, A: 255}
+)
+
+func TestCanvasHalfBlocks(t *testing.T) {
+	canvas := NewCanvas(3, 1, HalfBlocks)
+	canvas.Set(0, 0, testRed)
+	canvas.Set(1, 1, testRed)
+	canvas.Set(2, 0, testRed)
+	canvas.Set(2, 1, testBlue)
+
+	expected := []struct {
+		glyph  rune
+		fColor sdl.Color
+		bColor sdl.Color
+	}{
+		{'▀', testRed, NoColor},
+		{'▄', testRed, NoColor},
+		{'▀', testRed, testBlue},
+	}
+	for col, e := range expected {
+		glyph, fColor, bColor, ok := canvas.Cell(col, 0)
+		if !ok || glyph != e.glyph || fColor != e.fColor || bColor != e.bColor {
+			t.Errorf("Cell %v: expected %q %+v %+v but got %q %+v %+v", col, e.glyph, e.fColor, e.bColor, glyph, fColor, bColor)
+		}
+	}
+}
+
+func TestCanvasQuadrantsPickMostCommonForeground(t *testing.T) {
+	canvas := NewCanvas(1, 1, QuadrantBlocks)
+	canvas.Set(0, 0, testBlue)
+	canvas.Set(1, 0, testRed)
+	canvas.Set(0, 1, testRed)
+	canvas.Set(1, 1, testRed)
+
+	glyph, fColor, bColor, _ := canvas.Cell(0, 0)
+	if glyph != '▟' || fColor != testRed || bColor != testBlue {
+		t.Errorf("Expected '▟' red on blue but got %q %+v %+v", glyph, fColor, bColor)
+	}
+}
+
+func TestCanvasBrailleDots(t *testing.T) {
+	canvas := NewCanvas(1, 1, Braille)
+	canvas.Set(0, 0, testRed)
+	canvas.Set(1, 3, testRed)
+
+	glyph, _, _, _ := canvas.Cell(0, 0)
+	if glyph != rune(0x2800+0x01+0x80) {
+		t.Errorf("Expected braille dots 1 and 8 but got %U", glyph)
+	}
+}
+
+func TestCanvasEmptyCellIsSkipped(t *testing.T) {
+	canvas := NewCanvas(1, 1, Braille)
+	if _, _, _, ok := canvas.Cell(0, 0); ok {
+		t.Errorf("Expected an empty cell to report !ok")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thomas-holmes/gterm"
+)
+
+// parseCodepoints reads a set of codepoints. "cp437" is the 256 glyphs of
+// code page 437 in order and "font" every glyph a bitmap font has. Anything
+// else is a comma separated list of codepoints and ranges, written as 0x2500,
+// U+2500, 9472 or a single character.
+func parseCodepoints(spec string, fontRunes []rune) ([]rune, error) {
+	switch spec {
+	case "cp437":
+		runes := make([]rune, 256)
+		for i := range runes {
+			runes[i] = gterm.CP437.DecodeByte(byte(i))
+		}
+		return runes, nil
+	case "font":
+		if fontRunes == nil {
+			return nil, fmt.Errorf("Can't list the glyphs of this font, give the codepoints to use")
+		}
+		runes := append([]rune(nil), fontRunes...)
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+		return runes, nil
+	}
+
+	var runes []rune
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if from, to, ok := splitRange(item); ok {
+			first, err := parseCodepoint(from)
+			if err != nil {
+				return nil, err
+			}
+			last, err := parseCodepoint(to)
+			if err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("Range %q runs backwards", item)
+			}
+			for r := first; r <= last; r++ {
+				runes = append(runes, r)
+			}
+			continue
+		}
+		r, err := parseCodepoint(item)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, r)
+	}
+	if len(runes) == 0 {
+		return nil, fmt.Errorf("No codepoints in %q", spec)
+	}
+	return runes, nil
+}
+
+// splitRange splits "a-b", a lone "-" is the character rather than a range
+func splitRange(item string) (string, string, bool) {
+	dash := strings.Index(item[1:], "-")
+	if dash < 0 {
+		return "", "", false
+	}
+	dash++
+	return item[:dash], item[dash+1:], true
+}
+
+func parseCodepoint(text string) (rune, error) {
+	if utf8.RuneCountInString(text) == 1 {
+		r, _ := utf8.DecodeRuneInString(text)
+		return r, nil
+	}
+
+	number, base := text, 10
+	switch {
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X"):
+		number, base = text[2:], 16
+	case strings.HasPrefix(text, "U+") || strings.HasPrefix(text, "u+"):
+		number, base = text[2:], 16
+	}
+	value, err := strconv.ParseUint(number, base, 32)
+	if err != nil || value > utf8.MaxRune {
+		return 0, fmt.Errorf("Can't read codepoint %q", text)
+	}
+	return rune(value), nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCodepoints(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []rune
+	}{
+		{"0x41-0x43", []rune{'A', 'B', 'C'}},
+		{"U+2500,9474, @", []rune{'─', '│', '@'}},
+		{"a-c,-", []rune{'a', 'b', 'c', '-'}},
+		{"font", []rune{'a', 'z'}},
+	}
+	for _, test := range tests {
+		got, err := parseCodepoints(test.spec, []rune{'z', 'a'})
+		if err != nil {
+			t.Errorf("%q: %v", test.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: expected %q, got %q", test.spec, test.want, got)
+		}
+	}
+}
+
+func TestParseCodepointsCP437(t *testing.T) {
+	runes, err := parseCodepoints("cp437", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runes) != 256 || runes['A'] != 'A' || runes[0xC4] != '─' || runes[0xDB] != '█' {
+		t.Errorf("Expected code page 437 in order, got %q", runes)
+	}
+}
+
+func TestParseCodepointsErrors(t *testing.T) {
+	for _, spec := range []string{"0x43-0x41", "zebra", "", "font"} {
+		if _, err := parseCodepoints(spec, nil); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
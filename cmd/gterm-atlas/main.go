@@ -0,0 +1,154 @@
+// Command gterm-atlas draws a TTF, BDF or PSF font into a PNG sprite sheet
+// and writes a tileset descriptor next to it, ready for gterm.LoadAtlas.
+//
+//	gterm-atlas -font DejaVuSansMono.ttf -cell 12x24 -codepoints cp437
+//
+// CP437 sheets keep the code page's order with 16 columns, so they also work
+// as the window's main font and in font configs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/draw"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/thomas-holmes/gterm"
+)
+
+// glyphSource draws single glyphs of a font for the sheet
+type glyphSource interface {
+	// Glyph draws a rune, ok is false when the source knows the font has nothing for it
+	Glyph(r rune) (glyph image.Image, ok bool)
+	// Runes lists every rune the font has a glyph for, nil if it can't tell
+	Runes() []rune
+	Close()
+}
+
+// pack draws each rune's glyph into its own cell, in order, centred in cells
+// of cellW x cellH. Runes the font lacks leave their cell empty.
+func pack(source glyphSource, runes []rune, cellW int, cellH int, columns int) (atlas *gterm.Atlas, missing []rune) {
+	rows := (len(runes) + columns - 1) / columns
+	atlas = &gterm.Atlas{
+		Image:      image.NewRGBA(image.Rect(0, 0, columns*cellW, rows*cellH)),
+		CellWidth:  cellW,
+		CellHeight: cellH,
+		Columns:    columns,
+		Glyphs:     make(map[rune]int),
+	}
+
+	for index, r := range runes {
+		glyph, ok := source.Glyph(r)
+		if !ok {
+			missing = append(missing, r)
+			continue
+		}
+
+		cell := atlas.CellRect(index)
+		bounds := glyph.Bounds()
+		offset := image.Pt((cellW-bounds.Dx())/2, (cellH-bounds.Dy())/2)
+		dest := image.Rectangle{Min: cell.Min.Add(offset), Max: cell.Min.Add(offset).Add(bounds.Size())}.Intersect(cell)
+		from := bounds.Min.Add(dest.Min.Sub(cell.Min.Add(offset)))
+		draw.Draw(atlas.Image, dest, glyph, from, draw.Over)
+
+		if _, taken := atlas.Glyphs[r]; !taken {
+			atlas.Glyphs[r] = index
+		}
+	}
+	return atlas, missing
+}
+
+// parseCellSize reads a cell size written as WxH
+func parseCellSize(size string) (int, int, error) {
+	var w, h int
+	if _, err := fmt.Sscanf(size, "%dx%d", &w, &h); err != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("Cell size should look like 12x12, got %q", size)
+	}
+	return w, h, nil
+}
+
+func isBitmapFont(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range []string{".bdf", ".psf", ".psfu", ".psf.gz", ".psfu.gz"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	fontPath := flag.String("font", "", "TTF, BDF or PSF `file` to draw glyphs from")
+	cell := flag.String("cell", "", "cell size as `WxH`, bitmap fonts default to their own size")
+	size := flag.Int("size", 0, "TTF point `size`, defaults to the largest that fits the cell")
+	codepoints := flag.String("codepoints", "cp437", "`set` of codepoints: cp437, font for every glyph of a bitmap font, or a list like 0x20-0x7E,U+2500-U+257F,@")
+	columns := flag.Int("columns", 16, "cells across the sheet")
+	out := flag.String("out", "", "descriptor `path`, the sheet goes next to it. Defaults to the font name and cell size, like font_12x12.json")
+	flag.Parse()
+
+	if *fontPath == "" {
+		flag.Usage()
+		log.Fatalln("A font is needed")
+	}
+	if *columns <= 0 {
+		log.Fatalln("Columns must be positive")
+	}
+
+	var cellW, cellH int
+	if *cell != "" {
+		var err error
+		if cellW, cellH, err = parseCellSize(*cell); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	var source glyphSource
+	if isBitmapFont(*fontPath) {
+		atlas, err := gterm.LoadAtlas(*fontPath)
+		if err != nil {
+			log.Fatalln("Failed to load", *fontPath, err)
+		}
+		if *cell == "" {
+			cellW, cellH = atlas.CellWidth, atlas.CellHeight
+		}
+		source = bitmapSource{atlas}
+	} else {
+		if *cell == "" {
+			log.Fatalln("TTF fonts need a -cell size")
+		}
+		ttfSource, err := openTTF(*fontPath, *size, cellW, cellH)
+		if err != nil {
+			log.Fatalln("Failed to load", *fontPath, err)
+		}
+		source = ttfSource
+	}
+	defer source.Close()
+
+	runes, err := parseCodepoints(*codepoints, source.Runes())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sheetColumns := *columns
+	if *codepoints == "cp437" {
+		sheetColumns = 16
+	}
+
+	atlas, missing := pack(source, runes, cellW, cellH, sheetColumns)
+	if len(missing) > 0 {
+		log.Printf("No glyphs drawn for %v of the codepoints, their cells are empty", len(missing))
+	}
+
+	path := *out
+	if path == "" {
+		name := filepath.Base(*fontPath)
+		name = name[:strings.Index(name+".", ".")]
+		path = fmt.Sprintf("%v_%vx%v.json", name, cellW, cellH)
+	}
+	if err := gterm.SaveTileset(path, atlas); err != nil {
+		log.Fatalln("Failed to write", path, err)
+	}
+	log.Printf("Wrote %v glyphs of %vx%v to %v", len(atlas.Glyphs), cellW, cellH, path)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"unicode"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// bitmapSource takes glyphs from a BDF or PSF font
+type bitmapSource struct {
+	atlas *gterm.Atlas
+}
+
+func (source bitmapSource) Glyph(r rune) (image.Image, bool) {
+	index, ok := source.atlas.Lookup(r)
+	if !ok {
+		return nil, false
+	}
+	return source.atlas.Image.SubImage(source.atlas.CellRect(index)), true
+}
+
+func (source bitmapSource) Runes() []rune {
+	runes := make([]rune, 0, len(source.atlas.Glyphs))
+	for r := range source.atlas.Glyphs {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+func (source bitmapSource) Close() {}
+
+// ttfSource renders glyphs with SDL_ttf
+type ttfSource struct {
+	font *ttf.Font
+}
+
+// openTTF opens a TTF at size, or the largest size whose glyphs fit cellW x cellH
+func openTTF(path string, size int, cellW int, cellH int) (*ttfSource, error) {
+	if err := ttf.Init(); err != nil {
+		return nil, err
+	}
+	if size > 0 {
+		font, err := ttf.OpenFont(path, size)
+		if err != nil {
+			return nil, err
+		}
+		return &ttfSource{font: font}, nil
+	}
+
+	for size := cellH * 2; size > 0; size-- {
+		font, err := ttf.OpenFont(path, size)
+		if err != nil {
+			return nil, err
+		}
+		w, _, err := font.SizeUTF8("W")
+		if err == nil && font.Height() <= cellH && w <= cellW {
+			return &ttfSource{font: font}, nil
+		}
+		font.Close()
+	}
+	return nil, errors.New("No point size fits the cell")
+}
+
+// Glyph renders r if it is printable. This version of SDL_ttf can't tell
+// whether the font has a glyph for r, runes it lacks come out as the font's
+// missing glyph box rather than being reported missing.
+func (source *ttfSource) Glyph(r rune) (image.Image, bool) {
+	if !unicode.IsPrint(r) {
+		return nil, false
+	}
+	surface, err := source.font.RenderUTF8_Blended(string(r), sdl.Color{R: 255, G: 255, B: 255, A: 255})
+	if err != nil {
+		return nil, false
+	}
+	defer surface.Free()
+
+	converted, err := surface.ConvertFormat(uint32(sdl.PIXELFORMAT_RGBA32), 0)
+	if err != nil {
+		return nil, false
+	}
+	defer converted.Free()
+
+	glyph := image.NewRGBA(image.Rect(0, 0, int(converted.W), int(converted.H)))
+	pixels := converted.Pixels()
+	for y := 0; y < int(converted.H); y++ {
+		copy(glyph.Pix[y*glyph.Stride:(y+1)*glyph.Stride], pixels[y*int(converted.Pitch):])
+	}
+	return glyph, true
+}
+
+// Runes is unknown, this version of SDL_ttf can't ask a font which glyphs it has
+func (source *ttfSource) Runes() []rune {
+	return nil
+}
+
+func (source *ttfSource) Close() {
+	source.font.Close()
+	ttf.Quit()
+}
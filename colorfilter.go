@@ -0,0 +1,157 @@
+package gterm
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// ColorFilter changes every colour the window draws, for players who see
+// colour differently. It applies at compose time after lighting and post
+// effects, so apps keep using their own palette.
+type ColorFilter interface {
+	FilterColor(color sdl.Color) sdl.Color
+}
+
+// SetColorFilter filters every colour drawn from the next frame, nil turns filtering off
+func (window *Window) SetColorFilter(filter ColorFilter) {
+	window.colorFilter = filter
+	window.redrawAll = true
+}
+
+// ColorFilter returns the filter in use, nil when there isn't one
+func (window *Window) ColorFilter() ColorFilter {
+	return window.colorFilter
+}
+
+// ColorBlindness is a kind of colour vision deficiency
+type ColorBlindness int
+
+const (
+	// Protanopia is missing red cones, reds look dark and close to greens
+	Protanopia ColorBlindness = iota
+	// Deuteranopia is missing green cones, the most common, reds and greens look alike
+	Deuteranopia
+	// Tritanopia is missing blue cones, blues look like greens and yellows like pinks
+	Tritanopia
+)
+
+func (kind ColorBlindness) String() string {
+	switch kind {
+	case Protanopia:
+		return "Protanopia"
+	case Deuteranopia:
+		return "Deuteranopia"
+	case Tritanopia:
+		return "Tritanopia"
+	}
+	return "Unknown"
+}
+
+type colorMatrix [3][3]float64
+
+// simulations are from Machado, Oliveira and Fernandes 2009 at full severity,
+// they work on linear RGB
+var simulations = map[ColorBlindness]colorMatrix{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// corrections move the colour a player can't see into channels they can,
+// red-green losses into blue and brightness, blue losses into red and green
+var corrections = map[ColorBlindness]colorMatrix{
+	Protanopia:   {{0, 0, 0}, {0.7, 1, 0}, {0.7, 0, 1}},
+	Deuteranopia: {{0, 0, 0}, {0.7, 1, 0}, {0.7, 0, 1}},
+	Tritanopia:   {{1, 0, 0.7}, {0, 1, 0.7}, {0, 0, 0}},
+}
+
+func (matrix colorMatrix) apply(r, g, b float64) (float64, float64, float64) {
+	return matrix[0][0]*r + matrix[0][1]*g + matrix[0][2]*b,
+		matrix[1][0]*r + matrix[1][1]*g + matrix[1][2]*b,
+		matrix[2][0]*r + matrix[2][1]*g + matrix[2][2]*b
+}
+
+// linearValues maps sRGB channel values to linear light
+var linearValues = func() (values [256]float64) {
+	for i := range values {
+		c := float64(i) / 255
+		if c <= 0.04045 {
+			values[i] = c / 12.92
+		} else {
+			values[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	return values
+}()
+
+// srgbValue maps linear light back to an sRGB channel value
+func srgbValue(c float64) uint8 {
+	if c <= 0 {
+		return 0
+	}
+	if c >= 1 {
+		return 255
+	}
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(c*255 + 0.5)
+}
+
+func clampChannel(c float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, c+0.5)))
+}
+
+// SimulateColorBlindness shows the frame as a player with Kind would see it,
+// to check a palette still reads without telling red from green
+type SimulateColorBlindness struct {
+	Kind ColorBlindness
+}
+
+func (simulate SimulateColorBlindness) FilterColor(color sdl.Color) sdl.Color {
+	r, g, b := simulations[simulate.Kind].apply(linearValues[color.R], linearValues[color.G], linearValues[color.B])
+	return sdl.Color{R: srgbValue(r), G: srgbValue(g), B: srgbValue(b), A: color.A}
+}
+
+// CorrectColorBlindness shifts colours a player with Kind confuses apart, the
+// difference they can't see is moved into channels they can (daltonization)
+type CorrectColorBlindness struct {
+	Kind ColorBlindness
+}
+
+func (correct CorrectColorBlindness) FilterColor(color sdl.Color) sdl.Color {
+	r, g, b := float64(color.R), float64(color.G), float64(color.B)
+	simulated := SimulateColorBlindness{Kind: correct.Kind}.FilterColor(color)
+	lostR, lostG, lostB := corrections[correct.Kind].apply(r-float64(simulated.R), g-float64(simulated.G), b-float64(simulated.B))
+	return sdl.Color{R: clampChannel(r + lostR), G: clampChannel(g + lostG), B: clampChannel(b + lostB), A: color.A}
+}
+
+// HighContrast saturates colours and pushes them towards black or white, so
+// dim glyphs stand out from dark backgrounds
+type HighContrast struct{}
+
+func (HighContrast) FilterColor(color sdl.Color) sdl.Color {
+	const saturation, contrast = 1.5, 1.8
+	r, g, b := float64(color.R), float64(color.G), float64(color.B)
+	luma := 0.299*r + 0.587*g + 0.114*b
+	stretch := func(c float64) uint8 {
+		c = luma + (c-luma)*saturation
+		return clampChannel((c-128)*contrast + 128)
+	}
+	return sdl.Color{R: stretch(r), G: stretch(g), B: stretch(b), A: color.A}
+}
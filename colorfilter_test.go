@@ -0,0 +1,55 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestFiltersKeepGreysAndAlpha(t *testing.T) {
+	filters := []ColorFilter{
+		SimulateColorBlindness{Kind: Protanopia},
+		SimulateColorBlindness{Kind: Deuteranopia},
+		SimulateColorBlindness{Kind: Tritanopia},
+		CorrectColorBlindness{Kind: Deuteranopia},
+	}
+	for _, filter := range filters {
+		for _, grey := range []uint8{0, 128, 255} {
+			color := sdl.Color{R: grey, G: grey, B: grey, A: 77}
+			got := filter.FilterColor(color)
+			if colorDistance(got, color) > 9 || got.A != 77 {
+				t.Errorf("%#v changed grey %v to %v", filter, color, got)
+			}
+		}
+	}
+}
+
+func TestCorrectionSeparatesRedAndGreen(t *testing.T) {
+	red := sdl.Color{R: 220, G: 40, B: 40, A: 255}
+	green := sdl.Color{R: 40, G: 180, B: 40, A: 255}
+
+	for _, kind := range []ColorBlindness{Protanopia, Deuteranopia} {
+		simulate := SimulateColorBlindness{Kind: kind}
+		correct := CorrectColorBlindness{Kind: kind}
+
+		before := colorDistance(simulate.FilterColor(red), simulate.FilterColor(green))
+		after := colorDistance(simulate.FilterColor(correct.FilterColor(red)), simulate.FilterColor(correct.FilterColor(green)))
+		if after <= before {
+			t.Errorf("%v: expected correction to separate red and green, squared distance went from %v to %v", kind, before, after)
+		}
+	}
+}
+
+func TestHighContrast(t *testing.T) {
+	filter := HighContrast{}
+	if got := filter.FilterColor(sdl.Color{R: 40, G: 40, B: 40, A: 255}); got != (sdl.Color{A: 255}) {
+		t.Errorf("Expected dark grey to go black, got %v", got)
+	}
+	if got := filter.FilterColor(sdl.Color{R: 220, G: 220, B: 220, A: 255}); got != (sdl.Color{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("Expected light grey to go white, got %v", got)
+	}
+	dim := sdl.Color{R: 150, G: 60, B: 60, A: 255}
+	if got := filter.FilterColor(dim); got.R <= dim.R || got.G >= dim.G {
+		t.Errorf("Expected %v to get more vivid, got %v", dim, got)
+	}
+}
@@ -0,0 +1,134 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// DecorationStyle selects what a decoration draws
+type DecorationStyle int
+
+const (
+	// Highlight fills the cell with a translucent colour, the glyph stays on top
+	Highlight DecorationStyle = iota
+	// Outline draws a rectangle around the edge of the cell
+	Outline
+	// Underline draws a bar along the bottom of the cell
+	Underline
+	// CornerBrackets draws the four corners of a rectangle around the cell
+	CornerBrackets
+)
+
+// Decoration is drawn in pixel space on top of a cell without replacing its
+// contents. Like the background colour, decorations are reset by ClearCell and
+// ClearWindow.
+type Decoration struct {
+	Style DecorationStyle
+	Color sdl.Color
+	// Blink hides and shows the decoration every Blink milliseconds, zero never blinks
+	Blink uint32
+}
+
+// BlinkingCursor is an outline that blinks twice a second
+func BlinkingCursor(color sdl.Color) Decoration {
+	return Decoration{Style: Outline, Color: color, Blink: 500}
+}
+
+// Decorate adds a decoration to a cell, drawn above anything already decorating it
+func (window *Window) Decorate(col int, row int, decoration Decoration) error {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return err
+	}
+	window.cells[index].decorations = append(window.cells[index].decorations, decoration)
+	window.cells[index].advanceBlinking(sdl.GetTicks())
+	return nil
+}
+
+// advanceBlinking works out which blinking decorations are showing at ticks.
+// When the cell has any that blink it reports the tick the next one toggles.
+func (cell *cell) advanceBlinking(ticks uint32) (next uint32, blinking bool) {
+	cell.blinkHidden = 0
+	for i, decoration := range cell.decorations {
+		if decoration.Blink == 0 {
+			continue
+		}
+		toggle := ticks - ticks%decoration.Blink + decoration.Blink
+		if !blinking || toggle < next {
+			next = toggle
+		}
+		blinking = true
+		if (ticks/decoration.Blink)%2 == 1 && i < 32 {
+			cell.blinkHidden |= 1 << uint(i)
+		}
+	}
+	return next, blinking
+}
+
+// decorationThickness is how many pixels wide decoration lines are, growing with the cell size
+func (window *Window) decorationThickness() int32 {
+	thickness := int32(window.DisplayHPixel / 12)
+	if thickness < 1 {
+		return 1
+	}
+	return thickness
+}
+
+// decorationRects breaks a decoration into the rectangles filling it inside dest
+func decorationRects(style DecorationStyle, dest sdl.Rect, thickness int32) []sdl.Rect {
+	switch style {
+	case Highlight:
+		return []sdl.Rect{dest}
+	case Outline:
+		return []sdl.Rect{
+			{X: dest.X, Y: dest.Y, W: dest.W, H: thickness},
+			{X: dest.X, Y: dest.Y + dest.H - thickness, W: dest.W, H: thickness},
+			{X: dest.X, Y: dest.Y + thickness, W: thickness, H: dest.H - 2*thickness},
+			{X: dest.X + dest.W - thickness, Y: dest.Y + thickness, W: thickness, H: dest.H - 2*thickness},
+		}
+	case Underline:
+		return []sdl.Rect{{X: dest.X, Y: dest.Y + dest.H - thickness, W: dest.W, H: thickness}}
+	case CornerBrackets:
+		armW, armH := dest.W/3, dest.H/3
+		right, bottom := dest.X+dest.W, dest.Y+dest.H
+		return []sdl.Rect{
+			{X: dest.X, Y: dest.Y, W: armW, H: thickness},
+			{X: dest.X, Y: dest.Y, W: thickness, H: armH},
+			{X: right - armW, Y: dest.Y, W: armW, H: thickness},
+			{X: right - thickness, Y: dest.Y, W: thickness, H: armH},
+			{X: dest.X, Y: bottom - thickness, W: armW, H: thickness},
+			{X: dest.X, Y: bottom - armH, W: thickness, H: armH},
+			{X: right - armW, Y: bottom - thickness, W: armW, H: thickness},
+			{X: right - thickness, Y: bottom - armH, W: thickness, H: armH},
+		}
+	}
+	return nil
+}
+
+// renderDecorations draws a cell's visible decorations. Highlights cover the
+// glyphs, so the glyphs are drawn again on top of them.
+func (window *Window) renderDecorations(cell cell, destRect sdl.Rect) error {
+	thickness := window.decorationThickness()
+	for i, decoration := range cell.decorations {
+		if i < 32 && cell.blinkHidden&(1<<uint(i)) != 0 {
+			continue
+		}
+
+		color := window.transformColor(applyLight(decoration.Color, cell.light))
+		if err := window.SdlRenderer.SetDrawColor(color.R, color.G, color.B, color.A); err != nil {
+			return err
+		}
+		if err := window.SdlRenderer.FillRects(decorationRects(decoration.Style, destRect, thickness)); err != nil {
+			return err
+		}
+
+		if decoration.Style == Highlight {
+			for _, item := range cell.renderItems {
+				item.FColor = applyLight(item.FColor, cell.light)
+				if err := window.renderGlyph(item, NoColor, destRect); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestDecorationRectsStayInsideCell(t *testing.T) {
+	dest := sdl.Rect{X: 16, Y: 32, W: 8, H: 12}
+	for _, style := range []DecorationStyle{Highlight, Outline, Underline, CornerBrackets} {
+		rects := decorationRects(style, dest, 1)
+		if len(rects) == 0 {
+			t.Errorf("Style %v drew nothing", style)
+		}
+		for _, rect := range rects {
+			if rect.X < dest.X || rect.Y < dest.Y || rect.X+rect.W > dest.X+dest.W || rect.Y+rect.H > dest.Y+dest.H {
+				t.Errorf("Style %v rect %+v leaves the cell %+v", style, rect, dest)
+			}
+		}
+	}
+}
+
+func TestUnderlineSitsOnBottomEdge(t *testing.T) {
+	rects := decorationRects(Underline, sdl.Rect{X: 0, Y: 0, W: 8, H: 12}, 2)
+	expected := sdl.Rect{X: 0, Y: 10, W: 8, H: 2}
+	if len(rects) != 1 || rects[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, rects)
+	}
+}
+
+func TestAdvanceBlinking(t *testing.T) {
+	c := cell{decorations: []Decoration{
+		{Style: Outline},
+		{Style: Underline, Blink: 100},
+	}}
+
+	if next, ok := c.advanceBlinking(50); !ok || next != 100 || c.blinkHidden != 0 {
+		t.Errorf("Expected the blinking decoration showing at 50 until 100, hidden %b until %v", c.blinkHidden, next)
+	}
+	if next, ok := c.advanceBlinking(150); !ok || next != 200 || c.blinkHidden != 2 {
+		t.Errorf("Expected only the blinking decoration hidden at 150 until 200, hidden %b until %v", c.blinkHidden, next)
+	}
+
+	c.decorations = append(c.decorations, Decoration{Style: Highlight, Blink: 60})
+	if next, _ := c.advanceBlinking(150); next != 180 {
+		t.Errorf("Expected the faster blink to toggle first at 180, got %v", next)
+	}
+
+	still := cell{decorations: []Decoration{{Style: Outline}}}
+	if _, ok := still.advanceBlinking(150); ok {
+		t.Error("Expected a cell without blinking decorations not to blink")
+	}
+}
@@ -0,0 +1,172 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Cells are drawn into a target texture that persists between frames so
+// Refresh only has to redraw the cells that changed since they were last drawn.
+// window.drawn remembers what each cell looked like when it was drawn.
+
+func cellsEqual(a cell, b cell) bool {
+	if a.bgColor != b.bgColor || a.light != b.light {
+		return false
+	}
+	if a.animation != b.animation || a.frame != b.frame || a.blinkHidden != b.blinkHidden {
+		return false
+	}
+	if len(a.renderItems) != len(b.renderItems) || len(a.decorations) != len(b.decorations) {
+		return false
+	}
+	for i, decoration := range a.decorations {
+		if decoration != b.decorations[i] {
+			return false
+		}
+	}
+	for i, item := range a.renderItems {
+		if item != b.renderItems[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// resetTarget throws away the cached frame so the next Refresh redraws every cell
+func (window *Window) resetTarget() {
+	if window.target != nil {
+		window.target.Destroy()
+		window.target = nil
+	}
+	window.redrawAll = true
+}
+
+func (window *Window) ensureTarget() error {
+	if window.target != nil || !window.SdlRenderer.RenderTargetSupported() {
+		return nil
+	}
+
+	w, h := window.Columns*window.DisplayWPixel, window.Rows*window.DisplayHPixel
+	target, err := window.SdlRenderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, w, h)
+	if err != nil {
+		return err
+	}
+	if err := target.SetBlendMode(sdl.BLENDMODE_NONE); err != nil {
+		target.Destroy()
+		return err
+	}
+	window.target = target
+	window.redrawAll = true
+	return nil
+}
+
+// needsRedraw reports whether anything on screen would change. Effects change
+// every cell, so any frame with an effect running, or the first frame after the
+// last one stopped, redraws everything.
+func (window *Window) needsRedraw() bool {
+	if len(window.effects) > 0 || window.effectsDrawn {
+		window.redrawAll = true
+	}
+	if window.redrawAll || len(window.drawn) != len(window.cells) {
+		window.redrawAll = true
+		return true
+	}
+	for i := range window.cells {
+		if !cellsEqual(window.cells[i], window.drawn[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// remember records what a cell looked like when it was drawn, reusing the old record's storage
+func (window *Window) remember(index int) {
+	current := window.cells[index]
+	drawn := &window.drawn[index]
+	drawn.bgColor = current.bgColor
+	drawn.light = current.light
+	drawn.animation = current.animation
+	drawn.frame = current.frame
+	drawn.blinkHidden = current.blinkHidden
+	drawn.renderItems = append(drawn.renderItems[:0], current.renderItems...)
+	drawn.decorations = append(drawn.decorations[:0], current.decorations...)
+}
+
+func (window *Window) clearToBackground() error {
+	background := window.transformColor(window.backgroundColor)
+	if err := window.SdlRenderer.SetDrawColor(background.R, background.G, background.B, background.A); err != nil {
+		return err
+	}
+	return window.SdlRenderer.Clear()
+}
+
+// clearCell paints over a single cell with the background colour
+func (window *Window) clearCell(col int, row int) error {
+	background := window.transformColor(window.backgroundColor)
+	if err := window.SdlRenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE); err != nil {
+		return err
+	}
+	defer window.SdlRenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+
+	if err := window.SdlRenderer.SetDrawColor(background.R, background.G, background.B, background.A); err != nil {
+		return err
+	}
+	rect := sdl.Rect{X: int32(col * window.DisplayWPixel), Y: int32(row * window.DisplayHPixel), W: int32(window.DisplayWPixel), H: int32(window.DisplayHPixel)}
+	return window.SdlRenderer.FillRect(&rect)
+}
+
+// renderFrame brings the cached frame up to date and copies it to the screen
+func (window *Window) renderFrame() error {
+	if len(window.drawn) != len(window.cells) {
+		window.drawn = make([]cell, len(window.cells))
+	}
+	window.effectsDrawn = len(window.effects) > 0
+
+	if err := window.ensureTarget(); err != nil || window.target == nil {
+		// No render targets, draw every cell straight to the screen instead
+		if err := window.clearToBackground(); err != nil {
+			return err
+		}
+		for i := range window.cells {
+			window.remember(i)
+		}
+		window.redrawAll = false
+		return window.renderCells()
+	}
+
+	if err := window.SdlRenderer.SetRenderTarget(window.target); err != nil {
+		return err
+	}
+	defer window.SdlRenderer.SetRenderTarget(nil)
+
+	if window.redrawAll {
+		if err := window.clearToBackground(); err != nil {
+			return err
+		}
+	}
+	for row := 0; row < window.Rows; row++ {
+		for col := 0; col < window.Columns; col++ {
+			index := col + row*window.Columns
+			if !window.redrawAll {
+				if cellsEqual(window.cells[index], window.drawn[index]) {
+					continue
+				}
+				if err := window.clearCell(col, row); err != nil {
+					return err
+				}
+			}
+			if err := window.renderCell(col, row); err != nil {
+				return err
+			}
+			window.remember(index)
+		}
+	}
+	window.redrawAll = false
+
+	if err := window.SdlRenderer.SetRenderTarget(nil); err != nil {
+		return err
+	}
+	if err := window.clearToBackground(); err != nil {
+		return err
+	}
+	return window.SdlRenderer.Copy(window.target, nil, nil)
+}
@@ -0,0 +1,247 @@
+package gterm
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// PostEffect alters the frame after its cells have been composed. Every method
+// receives t, the effect's progress from 0 when it was added to 1 once its
+// duration has elapsed.
+type PostEffect interface {
+	// TransformColor adjusts a single colour of the composed frame.
+	TransformColor(color sdl.Color, t float64) sdl.Color
+	// Offset shifts the whole frame by a number of pixels.
+	Offset(t float64) (x int, y int)
+	// Overlay draws on top of the composed frame.
+	Overlay(window *Window, t float64) error
+}
+
+type activeEffect struct {
+	effect   PostEffect
+	start    uint32
+	duration uint32
+	hold     bool
+}
+
+func (active activeEffect) progress(ticks uint32) float64 {
+	if active.duration == 0 {
+		return 1
+	}
+	elapsed := float64(ticks - active.start)
+	return math.Min(elapsed/float64(active.duration), 1)
+}
+
+func (active activeEffect) done(ticks uint32) bool {
+	return !active.hold && ticks-active.start >= active.duration
+}
+
+// AddEffect starts a post effect that runs for duration milliseconds. Held
+// effects stay at their final state until removed, otherwise the effect is
+// dropped once it completes.
+func (window *Window) AddEffect(effect PostEffect, duration uint32, hold bool) {
+	window.effects = append(window.effects, activeEffect{
+		effect:   effect,
+		start:    sdl.GetTicks(),
+		duration: duration,
+		hold:     hold,
+	})
+}
+
+// RemoveEffect stops a running or held post effect
+func (window *Window) RemoveEffect(effect PostEffect) {
+	insertionIndex := 0
+	for _, active := range window.effects {
+		if active.effect != effect {
+			window.effects[insertionIndex] = active
+			insertionIndex++
+		}
+	}
+	window.effects = window.effects[:insertionIndex]
+}
+
+// ClearEffects stops every post effect
+func (window *Window) ClearEffects() {
+	window.effects = nil
+}
+
+// Effecting reports whether any post effect is still in progress
+func (window *Window) Effecting() bool {
+	ticks := sdl.GetTicks()
+	for _, active := range window.effects {
+		if active.progress(ticks) < 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// tidyEffects drops finished effects and records the progress of the rest for this frame
+func (window *Window) tidyEffects() {
+	ticks := sdl.GetTicks()
+	insertionIndex := 0
+	for _, active := range window.effects {
+		if !active.done(ticks) {
+			window.effects[insertionIndex] = active
+			insertionIndex++
+		}
+	}
+	window.effects = window.effects[:insertionIndex]
+
+	window.effectTicks = ticks
+	window.offsetX, window.offsetY = 0, 0
+	for _, active := range window.effects {
+		x, y := active.effect.Offset(active.progress(ticks))
+		window.offsetX += x
+		window.offsetY += y
+	}
+}
+
+func (window *Window) transformColor(color sdl.Color) sdl.Color {
+	for _, active := range window.effects {
+		color = active.effect.TransformColor(color, active.progress(window.effectTicks))
+	}
+	if window.colorFilter != nil {
+		color = window.colorFilter.FilterColor(color)
+	}
+	return color
+}
+
+func (window *Window) renderOverlays() error {
+	for _, active := range window.effects {
+		if err := active.effect.Overlay(window, active.progress(window.effectTicks)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lerp(a uint8, b uint8, t float64) uint8 {
+	return uint8(math.Floor(float64(a) + (float64(b)-float64(a))*t + 0.5))
+}
+
+func lerpColor(a sdl.Color, b sdl.Color, t float64) sdl.Color {
+	return sdl.Color{R: lerp(a.R, b.R, t), G: lerp(a.G, b.G, t), B: lerp(a.B, b.B, t), A: a.A}
+}
+
+// FadeEffect fades the frame to Color, or from Color back to the frame when From is set
+type FadeEffect struct {
+	Color sdl.Color
+	From  bool
+}
+
+func (fade *FadeEffect) TransformColor(color sdl.Color, t float64) sdl.Color {
+	if fade.From {
+		t = 1 - t
+	}
+	return lerpColor(color, fade.Color, t)
+}
+
+func (fade *FadeEffect) Offset(t float64) (int, int) {
+	return 0, 0
+}
+
+func (fade *FadeEffect) Overlay(window *Window, t float64) error {
+	return nil
+}
+
+// TintEffect blends every colour towards Color, reaching Amount (0-1) by the end of the effect
+type TintEffect struct {
+	Color  sdl.Color
+	Amount float64
+}
+
+func (tint *TintEffect) TransformColor(color sdl.Color, t float64) sdl.Color {
+	return lerpColor(color, tint.Color, tint.Amount*t)
+}
+
+func (tint *TintEffect) Offset(t float64) (int, int) {
+	return 0, 0
+}
+
+func (tint *TintEffect) Overlay(window *Window, t float64) error {
+	return nil
+}
+
+// DesaturateEffect drains colour from the frame, reaching Amount (0-1) by the end of the effect
+type DesaturateEffect struct {
+	Amount float64
+}
+
+func (desaturate *DesaturateEffect) TransformColor(color sdl.Color, t float64) sdl.Color {
+	luma := uint8(0.299*float64(color.R) + 0.587*float64(color.G) + 0.114*float64(color.B))
+	grey := sdl.Color{R: luma, G: luma, B: luma, A: color.A}
+	return lerpColor(color, grey, desaturate.Amount*t)
+}
+
+func (desaturate *DesaturateEffect) Offset(t float64) (int, int) {
+	return 0, 0
+}
+
+func (desaturate *DesaturateEffect) Overlay(window *Window, t float64) error {
+	return nil
+}
+
+// ShakeEffect jitters the frame by up to Magnitude pixels, settling as the effect finishes
+type ShakeEffect struct {
+	Magnitude int
+}
+
+func (shake *ShakeEffect) TransformColor(color sdl.Color, t float64) sdl.Color {
+	return color
+}
+
+func (shake *ShakeEffect) Offset(t float64) (int, int) {
+	if t >= 1 {
+		return 0, 0
+	}
+	// Deterministic wobble so a given point in the effect always lands in the same place
+	decay := float64(shake.Magnitude) * (1 - t)
+	x := math.Sin(t*97) * decay
+	y := math.Cos(t*61) * decay
+	return int(math.Floor(x + 0.5)), int(math.Floor(y + 0.5))
+}
+
+func (shake *ShakeEffect) Overlay(window *Window, t float64) error {
+	return nil
+}
+
+// ScanlineEffect darkens every Spacing'th pixel row, like an old CRT. Alpha
+// sets how dark the lines are and Flicker varies it over the effect.
+type ScanlineEffect struct {
+	Spacing int
+	Alpha   uint8
+	Flicker float64
+}
+
+func (scanline *ScanlineEffect) TransformColor(color sdl.Color, t float64) sdl.Color {
+	return color
+}
+
+func (scanline *ScanlineEffect) Offset(t float64) (int, int) {
+	return 0, 0
+}
+
+func (scanline *ScanlineEffect) Overlay(window *Window, t float64) error {
+	spacing := scanline.Spacing
+	if spacing < 2 {
+		spacing = 2
+	}
+
+	alpha := float64(scanline.Alpha) * (1 - scanline.Flicker*math.Abs(math.Sin(t*math.Pi*8)))
+	if err := window.SdlRenderer.SetDrawColor(0, 0, 0, uint8(alpha)); err != nil {
+		return err
+	}
+
+	w, h, err := window.SdlRenderer.GetOutputSize()
+	if err != nil {
+		return err
+	}
+	for y := 0; y < h; y += spacing {
+		if err := window.SdlRenderer.DrawLine(0, y, w, y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
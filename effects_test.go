@@ -0,0 +1,97 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestColorEffects(t *testing.T) {
+	red := sdl.Color{R: 200, G: 0, B: 0, A: 255}
+	black := sdl.Color{A: 255}
+	tests := []struct {
+		name     string
+		effect   PostEffect
+		progress float64
+		expected sdl.Color
+	}{
+		{"fade start", &FadeEffect{Color: black}, 0, red},
+		{"fade half", &FadeEffect{Color: black}, 0.5, sdl.Color{R: 100, A: 255}},
+		{"fade end", &FadeEffect{Color: black}, 1, black},
+		{"fade from start", &FadeEffect{Color: black, From: true}, 0, black},
+		{"fade from end", &FadeEffect{Color: black, From: true}, 1, red},
+		{"tint", &TintEffect{Color: sdl.Color{B: 200, A: 255}, Amount: 0.5}, 1, sdl.Color{R: 100, B: 100, A: 255}},
+		{"tint half way", &TintEffect{Color: sdl.Color{B: 200, A: 255}, Amount: 0.5}, 0.5, sdl.Color{R: 150, B: 50, A: 255}},
+		{"desaturate", &DesaturateEffect{Amount: 1}, 1, sdl.Color{R: 59, G: 59, B: 59, A: 255}},
+		{"desaturate start", &DesaturateEffect{Amount: 1}, 0, red},
+		{"shake", &ShakeEffect{Magnitude: 4}, 0.5, red},
+		{"scanline", &ScanlineEffect{Spacing: 2, Alpha: 100}, 0.5, red},
+	}
+	for _, test := range tests {
+		if got := test.effect.TransformColor(red, test.progress); got != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestFadeKeepsAlpha(t *testing.T) {
+	translucent := sdl.Color{R: 255, G: 255, B: 255, A: 100}
+	faded := (&FadeEffect{Color: sdl.Color{A: 255}}).TransformColor(translucent, 1)
+	if faded.A != 100 {
+		t.Errorf("Expected alpha to survive the fade, got %v", faded.A)
+	}
+}
+
+func TestShakeOffset(t *testing.T) {
+	shake := &ShakeEffect{Magnitude: 4}
+	if x, y := shake.Offset(1); x != 0 || y != 0 {
+		t.Errorf("Expected a finished shake to settle, got %v,%v", x, y)
+	}
+
+	moved := false
+	for step := 0; step < 10; step++ {
+		progress := float64(step) / 10
+		x, y := shake.Offset(progress)
+		limit := int(4*(1-progress) + 0.5)
+		if x < -limit || x > limit || y < -limit || y > limit {
+			t.Errorf("At %v expected offsets within %v, got %v,%v", progress, limit, x, y)
+		}
+		if again, _ := shake.Offset(progress); again != x {
+			t.Errorf("At %v expected the same offset each time, got %v then %v", progress, x, again)
+		}
+		moved = moved || x != 0 || y != 0
+	}
+	if !moved {
+		t.Error("Expected the shake to move the frame")
+	}
+
+	for _, effect := range []PostEffect{&FadeEffect{}, &TintEffect{}, &DesaturateEffect{}, &ScanlineEffect{}} {
+		if x, y := effect.Offset(0.5); x != 0 || y != 0 {
+			t.Errorf("Expected %T not to move the frame, got %v,%v", effect, x, y)
+		}
+	}
+}
+
+func TestEffectProgress(t *testing.T) {
+	tests := []struct {
+		active   activeEffect
+		ticks    uint32
+		progress float64
+		done     bool
+	}{
+		{activeEffect{start: 100, duration: 200}, 100, 0, false},
+		{activeEffect{start: 100, duration: 200}, 150, 0.25, false},
+		{activeEffect{start: 100, duration: 200}, 300, 1, true},
+		{activeEffect{start: 100, duration: 200}, 900, 1, true},
+		{activeEffect{start: 100, duration: 200, hold: true}, 900, 1, false},
+		{activeEffect{start: 100}, 100, 1, true},
+	}
+	for i, test := range tests {
+		if got := test.active.progress(test.ticks); got != test.progress {
+			t.Errorf("%v: expected progress %v, got %v", i, test.progress, got)
+		}
+		if got := test.active.done(test.ticks); got != test.done {
+			t.Errorf("%v: expected done %v, got %v", i, test.done, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/thomas-holmes/gterm"
@@ -8,12 +9,31 @@ import (
 )
 
 func main() {
+	bitmapFont := flag.String("bitmap", "", "draw with a PSF or BDF `font` instead of the CP437 sheets")
+	flag.Parse()
+
 	window := gterm.NewWindow(80, 40, "fonts/cp437_12x12.png", 12, 12, false)
+	if *bitmapFont != "" {
+		atlas, err := gterm.LoadAtlas(*bitmapFont)
+		if err != nil {
+			log.Fatalln("Failed to load bitmap font", err)
+		}
+		if err := window.UseAtlas(atlas); err != nil {
+			log.Fatalln("Failed to use bitmap font", err)
+		}
+	}
 
 	if err := window.Init(); err != nil {
 		log.Fatalln("Failed to init window", err)
 	}
 
+	fonts, err := gterm.LoadFontConfig("fonts/_config.xt")
+	if err != nil {
+		log.Fatalln("Failed to load font config", err)
+	}
+	window.UseFonts(fonts)
+	window.SetTitle(window.FontName())
+
 	w := window.SdlWindow
 	_ = w
 	r := window.SdlRenderer
@@ -95,14 +115,19 @@ func main() {
 			case *sdl.KeyDownEvent:
 				switch v.Keysym.Sym {
 				case sdl.K_1:
-					window.ChangeFont("fonts/cp437_8x8.png", 8, 8)
+					window.SetFont("CP437 8x8")
 				case sdl.K_2:
-					window.ChangeFont("fonts/cp437_12x12.png", 12, 12)
+					window.SetFont("CP437 12x12")
 				case sdl.K_3:
-					window.ChangeFont("fonts/cp437_16x16.png", 16, 16)
+					window.SetFont("CP437 16x16")
+				case sdl.K_TAB:
+					if err := window.CycleFont(); err != nil {
+						log.Println("Failed to change font", err)
+					}
 				case sdl.K_ESCAPE:
 					quit = true
 				}
+				window.SetTitle(window.FontName())
 			case *sdl.QuitEvent:
 				quit = true
 			}
@@ -13,13 +13,16 @@ type Animation interface {
 
 // Maybe make a LinearAnimation instead?
 type LinearSpellAnimation struct {
-	x int
-	y int
+	startX int
+	startY int
+	endX   int
+	endY   int
+
+	glyph *gterm.FloatingGlyph
 
 	startTime       uint32
 	accumulatedTime uint32
 	path            []Position
-	step            int
 
 	Delay uint32
 	Speed uint32
@@ -40,6 +43,11 @@ func NewLinearSpellAnimation(startX, startY, endX, endY int, speed uint32, delay
 		Glyph: glyph,
 		Delay: delay,
 		path:  PlotLine(startX, startY, endX, endY)[1:],
+
+		startX: startX,
+		startY: startY,
+		endX:   endX,
+		endY:   endY,
 	}
 }
 
@@ -54,20 +62,25 @@ func (a *LinearSpellAnimation) Update(delta uint32) {
 	}
 
 	a.accumulatedTime += delta
-	elapsed := a.accumulatedTime - (a.startTime + a.Delay) // beware underflow
-	a.step = int((elapsed) / a.Speed)
+	if a.Done() && a.glyph != nil {
+		a.glyph.Remove()
+		a.glyph = nil
+	}
 }
 
 func (a *LinearSpellAnimation) Ready() bool {
 	return a.accumulatedTime > a.startTime+a.Delay
 }
 
-// Interp?
+// Render launches the projectile on the floating layer the first time it is
+// ready, gterm glides it the rest of the way between cells
 func (a *LinearSpellAnimation) Render(world *World) {
-	if a.Done() || !a.Ready() {
+	if a.Done() || !a.Ready() || a.glyph != nil {
 		return
 	}
 
-	pos := a.path[a.step]
-	world.RenderRuneAt(pos.X, pos.Y, a.Glyph, a.Color, gterm.NoColor)
+	startX, startY := world.ScreenPosition(a.startX, a.startY)
+	endX, endY := world.ScreenPosition(a.endX, a.endY)
+	a.glyph = world.Window.AddFloatingGlyph(a.Glyph, a.Color, gterm.NoColor, startX, startY)
+	a.glyph.MoveTo(endX, endY, a.Speed*uint32(len(a.path)), gterm.Linear)
 }
@@ -244,6 +244,22 @@ func (creature *Creature) CastSpell(spell Spell, world *World, targetX int, targ
 	world.Broadcast(SpellLaunch, SpellLaunchMessage{Caster: creature, Spell: spell, X: targetX, Y: targetY})
 }
 
+// TakeStairs moves the player along the stairs they are standing on, if they lead the right way
+func (player *Creature) TakeStairs(kind TileKind, world *World) bool {
+	tile := world.CurrentLevel.GetTile(player.X, player.Y)
+	if tile.TileKind != kind {
+		return false
+	}
+	stair, ok := world.CurrentLevel.getStair(player.X, player.Y)
+	if !ok {
+		return false
+	}
+	player.Broadcast(PlayerFloorChange, PlayerFloorChangeMessage{
+		Stair: stair,
+	})
+	return true
+}
+
 // HandleInput updates player position based on user input
 func (player *Creature) HandleInput(input InputEvent, world *World) bool {
 	newX := player.X
@@ -255,86 +271,72 @@ func (player *Creature) HandleInput(input InputEvent, world *World) bool {
 	}
 
 	switch e := input.Event.(type) {
-	case *sdl.KeyDownEvent:
-		switch e.Keysym.Sym {
-		case sdl.K_COMMA:
-			if input.Keymod&sdl.KMOD_SHIFT > 0 {
-				tile := world.CurrentLevel.GetTile(player.X, player.Y)
-				if tile.TileKind == UpStair {
-					if stair, ok := world.CurrentLevel.getStair(player.X, player.Y); ok {
-						player.Broadcast(PlayerFloorChange, PlayerFloorChangeMessage{
-							Stair: stair,
-						})
-					} else {
-						return false
-					}
-				}
+	case *sdl.KeyDownEvent, *gterm.HitEvent:
+		// Debug keys stay out of the keymap, HUD clicks resolve to actions like keys do
+		if key, ok := e.(*sdl.KeyDownEvent); ok {
+			switch key.Keysym.Sym {
+			case sdl.K_1:
+				player.Damage(1)
+				return false
+			case sdl.K_2:
+				player.Heal(1)
+				return false
 			}
+		}
+
+		action, ok := input.Action(world.Keymap)
+		if !ok {
 			return false
-		case sdl.K_PERIOD:
-			if input.Keymod&sdl.KMOD_SHIFT > 0 {
-				tile := world.CurrentLevel.GetTile(player.X, player.Y)
-				if tile.TileKind == DownStair {
-					if stair, ok := world.CurrentLevel.getStair(player.X, player.Y); ok {
-						player.Broadcast(PlayerFloorChange, PlayerFloorChangeMessage{
-							Stair: stair,
-						})
-					} else {
-						return false
-					}
+		}
+
+		if dx, dy, ok := action.Direction(); ok {
+			newX, newY = player.X+dx, player.Y+dy
+		} else {
+			switch action {
+			case Ascend:
+				// Climbing doesn't use the turn, like it always has
+				player.TakeStairs(UpStair, world)
+				return false
+			case Descend:
+				// Off the stairs descending waits a turn, like it always has
+				if world.CurrentLevel.GetTile(player.X, player.Y).TileKind != DownStair {
+					return true
 				}
+				return player.TakeStairs(DownStair, world)
+			case gterm.Wait:
+				return true
+			case PickUp:
+				return player.PickupItem(world)
+			case OpenInventory:
+				menu := &InventoryPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, Inventory: player.Inventory}
+				player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
+				return false
+			case OpenEquipment:
+				menu := &EquipmentPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, Player: player}
+				player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
+				return false
+			case Inspect:
+				menu := &InspectionPop{PopMenu: PopMenu{X: 60, Y: 20, W: 30, H: 5}, World: world, InspectX: player.X, InspectY: player.Y}
+				player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
+				return false
+			case CastSpell:
+				menu := &SpellPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, World: world}
+				player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
+				return false
+			case ShowLog:
+				player.Broadcast(ShowFullGameLog, nil)
+				return false
+			case ShowHelp:
+				menu := &HelpPop{PopMenu: PopMenu{X: 10, Y: 2, W: 40, H: world.Window.Rows - 4}, Keymap: world.Keymap}
+				player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
+				return false
+			case Cancel:
+				world.GameOver = true
+				world.QuitGame = true
+				return true
+			default:
+				return false
 			}
-			// Period returns true because it means "wait"
-			return true
-		case sdl.K_h:
-			newX = player.X - 1
-		case sdl.K_j:
-			newY = player.Y + 1
-		case sdl.K_k:
-			newY = player.Y - 1
-		case sdl.K_l:
-			newX = player.X + 1
-		case sdl.K_b:
-			newX, newY = player.X-1, player.Y+1
-		case sdl.K_n:
-			newX, newY = player.X+1, player.Y+1
-		case sdl.K_y:
-			newX, newY = player.X-1, player.Y-1
-		case sdl.K_u:
-			newX, newY = player.X+1, player.Y-1
-		case sdl.K_1:
-			player.Damage(1)
-			return false
-		case sdl.K_2:
-			player.Heal(1)
-			return false
-		case sdl.K_g:
-			return player.PickupItem(world)
-		case sdl.K_i:
-			menu := &InventoryPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, Inventory: player.Inventory}
-			player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
-			return false
-		case sdl.K_e:
-			menu := &EquipmentPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, Player: player}
-			player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
-			return false
-		case sdl.K_x:
-			menu := &InspectionPop{PopMenu: PopMenu{X: 60, Y: 20, W: 30, H: 5}, World: world, InspectX: player.X, InspectY: player.Y}
-			player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
-			return false
-		case sdl.K_z:
-			menu := &SpellPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: world.Window.Rows - 4}, World: world}
-			player.Broadcast(ShowMenu, ShowMenuMessage{Menu: menu})
-			return false
-		case sdl.K_m:
-			player.Broadcast(ShowFullGameLog, nil)
-			return false
-		case sdl.K_ESCAPE:
-			world.GameOver = true
-			world.QuitGame = true
-			return true
-		default:
-			return false
 		}
 
 		if newX != player.X || newY != player.Y {
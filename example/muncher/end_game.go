@@ -1,7 +1,12 @@
 package main
 
-import "github.com/thomas-holmes/gterm"
-import "github.com/veandco/go-sdl2/sdl"
+import (
+	"log"
+	"strings"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
 
 type EndGameMenu struct {
 	world *World
@@ -11,10 +16,18 @@ type EndGameMenu struct {
 	ContentRelativeX int
 	ContentRelativeY int
 
+	Title     string
+	TitleFont gterm.BigFont
+
 	PopMenu
 }
 
 func (pop *EndGameMenu) Update(input InputEvent) bool {
+	if action, ok := input.Action(pop.world.Keymap); ok && (action == Confirm || action == Cancel) {
+		pop.world.QuitGame = true
+		pop.done = true
+		return true
+	}
 	return false
 }
 
@@ -43,9 +56,33 @@ func (pop *EndGameMenu) RenderContents(window *gterm.Window) {
 	}
 }
 
+// SetTitle draws title in a big font across the top of the menu, pushing the contents below it
+func (pop *EndGameMenu) SetTitle(title string, font gterm.BigFont) {
+	pop.Title = title
+	pop.TitleFont = font
+
+	titleHeight := font.Height() * (strings.Count(title, "\n") + 1)
+	pop.H = max(pop.H, titleHeight+len(pop.Content)+4)
+	pop.ContentRelativeY = titleHeight + 2
+}
+
+func (pop *EndGameMenu) RenderTitle(window *gterm.Window) {
+	if pop.TitleFont == nil {
+		return
+	}
+	for i, line := range strings.Split(pop.Title, "\n") {
+		xOffset := pop.X + (pop.W-gterm.BigTextWidth(line, pop.TitleFont))/2
+		yOffset := pop.Y + 1 + i*pop.TitleFont.Height()
+		if err := window.PutBigText(xOffset, yOffset, line, pop.TitleFont, pop.ContentColor); err != nil {
+			log.Println("Failed to render end game title", err)
+		}
+	}
+}
+
 func (pop *EndGameMenu) Render(window *gterm.Window) {
 	window.ClearRegion(pop.X, pop.Y, pop.W, pop.H)
 	pop.RenderBorder(window)
+	pop.RenderTitle(window)
 	pop.RenderContents(window)
 }
 
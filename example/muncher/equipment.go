@@ -37,6 +37,12 @@ func (pop *EquipmentPop) equipItem(index int) {
 }
 
 func (pop *EquipmentPop) Update(input InputEvent) bool {
+	// The list is drawn by InventoryPop so its entries are named the same
+	if index, ok := clickedEntry(input, "inventory"); ok {
+		pop.equipItem(index)
+		return false
+	}
+
 	switch e := input.Event.(type) {
 	case *sdl.KeyDownEvent:
 		k := e.Keysym.Sym
@@ -52,6 +52,9 @@ func (gameLog *GameLog) Render(window *gterm.Window) {
 // TODO: This will probably suck perf/allocation wise? Might be constantly
 // reallocating I push things back.
 func (gameLog *GameLog) appendMessages(messages []string) {
+	for _, message := range messages {
+		gameLog.world.Window.Announce(message)
+	}
 	gameLog.Messages = append(messages, gameLog.Messages...)
 }
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/thomas-holmes/gterm/gtermtest"
+)
+
+// Golden files live in testdata, record them with go test -update
+
+func newTestGame(t *testing.T, columns int, rows int) (*gterm.Window, *GameScene) {
+	keymap, err := gterm.LoadKeymapFile(path.Join("assets", "keys.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := gtermtest.NewWindow(columns, rows)
+	game := NewGame(window, keymap, &Settings{CameraCentered: true, Seed: 99})
+	game.step(InputEvent{})
+	return window, game
+}
+
+func renderGame(window *gterm.Window, game *GameScene) {
+	window.ClearWindow()
+	game.Render(window)
+}
+
+func TestHUDLayouts(t *testing.T) {
+	sizes := []struct{ columns, rows int }{
+		{100, 30},
+		{100, 40},
+		{140, 50},
+	}
+	for _, size := range sizes {
+		window, game := newTestGame(t, size.columns, size.rows)
+		renderGame(window, game)
+		gtermtest.AssertText(t, window, fmt.Sprintf("hud_%vx%v", size.columns, size.rows))
+	}
+}
+
+func TestPopups(t *testing.T) {
+	window, game := newTestGame(t, 100, 30)
+	world := game.World
+	player := world.Player
+
+	endGame := NewEndGameMenu(10, 5, 40, 6, Red, "YOU ARE VERY DEAD", "I AM SO SORRY :(", "", "Press Enter to return to the title")
+	endGame.world = world
+
+	popups := map[string]Menu{
+		"help":      &HelpPop{PopMenu: PopMenu{X: 10, Y: 2, W: 40, H: window.Rows - 4}, Keymap: world.Keymap},
+		"inventory": &InventoryPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: window.Rows - 4}, Inventory: player.Inventory},
+		"equipment": &EquipmentPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: window.Rows - 4}, Player: player},
+		"inspect":   &InspectionPop{PopMenu: PopMenu{X: 60, Y: 20, W: 30, H: 5}, World: world, InspectX: player.X, InspectY: player.Y},
+		"spells":    &SpellPop{PopMenu: PopMenu{X: 10, Y: 2, W: 30, H: window.Rows - 4}, World: world},
+		"game_log":  &FullGameLog{PopMenu: PopMenu{X: 5, Y: 0, W: 80, H: window.Rows - 2}, GameLog: world.GameLog},
+		"end_game":  &endGame,
+	}
+	for name, popup := range popups {
+		renderGame(window, game)
+		popup.Render(window)
+		gtermtest.AssertText(t, window, "popup_"+name)
+	}
+}
+
+func TestTitleAndOptions(t *testing.T) {
+	window := gtermtest.NewWindow(100, 30)
+	keymap, err := gterm.LoadKeymapFile(path.Join("assets", "keys.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := &Settings{CameraCentered: true, Seed: 99}
+
+	// The window has no font sheet to scale, so the title falls back to plain text
+	scenes := gterm.NewSceneStack(NewTitleScene(window, keymap, settings))
+	scenes.Render(window)
+	gtermtest.AssertText(t, window, "title")
+
+	scenes.Push(NewOptionsScene(window, keymap, settings))
+	scenes.Render(window)
+	gtermtest.AssertText(t, window, "options")
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/thomas-holmes/gterm"
+)
+
+// HelpPop lists every key binding
+type HelpPop struct {
+	Keymap *gterm.Keymap
+
+	PopMenu
+}
+
+func (pop *HelpPop) Update(input InputEvent) bool {
+	if action, ok := input.Action(pop.Keymap); ok && action == Cancel {
+		pop.done = true
+		return true
+	}
+	return false
+}
+
+func (pop *HelpPop) Render(window *gterm.Window) {
+	if err := window.ClearRegion(pop.X, pop.Y, pop.W, pop.H); err != nil {
+		log.Println("Failed to clear help region", err)
+	}
+
+	row := pop.Y
+	var lastAction gterm.Action
+	for _, binding := range pop.Keymap.Bindings() {
+		if row >= pop.Y+pop.H {
+			break
+		}
+		if binding.Action == lastAction {
+			window.PutString(pop.X+20, row, binding.Chord.String(), White)
+		} else {
+			window.PutString(pop.X, row, fmt.Sprintf("%-18v  %v", binding.Action, binding.Chord), White)
+		}
+		lastAction = binding.Action
+		row++
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
 )
 
 type HUD struct {
@@ -17,6 +18,8 @@ type HUD struct {
 	Height int
 
 	nextFreeRow int
+
+	minimap *gterm.Canvas
 }
 
 func NewHud(player *Creature, world *World, xPos int, yPos int) *HUD {
@@ -26,6 +29,7 @@ func NewHud(player *Creature, world *World, xPos int, yPos int) *HUD {
 		XPos:        xPos,
 		YPos:        yPos,
 		nextFreeRow: 0,
+		minimap:     gterm.NewCanvas(minimapSize, minimapSize, gterm.QuadrantBlocks),
 	}
 
 	world.messageBus.Subscribe(&hud)
@@ -43,8 +47,25 @@ func (hud *HUD) Notify(message Message, data interface{}) {
 	case PlayerUpdate:
 	}
 }
+
+// clickable makes rows of the HUD a hit region named after the action a click
+// on them does, and returns the colour to draw them in
+func (hud *HUD) clickable(world *World, action gterm.Action, row int, height int) sdl.Color {
+	world.Window.AddHitRegion(string(action), hud.XPos, row, world.Window.Columns-hud.XPos, height)
+	return hud.hoverColor(world, action)
+}
+
+// hoverColor lights up the HUD rows for action while the mouse is over them
+func (hud *HUD) hoverColor(world *World, action gterm.Action) sdl.Color {
+	if world.Window.Hovered() == string(action) {
+		return White
+	}
+	return Yellow
+}
+
 func (hud *HUD) renderPlayerName(world *World) {
-	world.Window.PutString(hud.XPos, hud.GetNextRow(), world.Player.Name, Yellow)
+	row := hud.GetNextRow()
+	world.Window.PutString(hud.XPos, row, world.Player.Name, hud.clickable(world, OpenInventory, row, 1))
 }
 
 func (hud *HUD) renderPlayerPosition(world *World) {
@@ -104,7 +125,7 @@ func (hud *HUD) renderPlayerMagic(world *World) {
 	}
 
 	row := hud.GetNextRow()
-	if err := world.Window.PutString(hud.XPos, row, label, Yellow); err != nil {
+	if err := world.Window.PutString(hud.XPos, row, label, hud.clickable(world, CastSpell, row, 1)); err != nil {
 		log.Fatalln("Couldn't write HUD mp label", err)
 	}
 
@@ -120,7 +141,8 @@ func (hud *HUD) renderPlayerLevel(world *World) {
 
 func (hud *HUD) renderTurnCount(world *World) {
 	turnCount := fmt.Sprintf("Turn: %v", world.turnCount)
-	world.Window.PutString(hud.XPos, hud.GetNextRow(), turnCount, Yellow)
+	row := hud.GetNextRow()
+	world.Window.PutString(hud.XPos, row, turnCount, hud.clickable(world, ShowLog, row, 1))
 }
 
 func (hud *HUD) renderEquippedWeapon(world *World) {
@@ -132,7 +154,11 @@ func (hud *HUD) renderEquippedWeapon(world *World) {
 	weaponStr := fmt.Sprintf("Weapon: %v", equipName)
 
 	offsetX = hud.XPos
-	hud.nextFreeRow += putWrappedText(world.Window, weaponStr, offsetX, offsetY, 0, 2, world.Window.Columns-offsetX, Yellow)
+	// The region's height isn't known until the name wraps, the hover colour doesn't need it
+	color := hud.hoverColor(world, OpenEquipment)
+	rows := putWrappedText(world.Window, weaponStr, offsetX, offsetY, 0, 2, world.Window.Columns-offsetX, color)
+	hud.nextFreeRow += rows
+	hud.clickable(world, OpenEquipment, offsetY, rows)
 }
 
 func (hud *HUD) renderItemDisplay(world *World) {
@@ -161,6 +187,36 @@ func (hud *HUD) renderItemDisplay(world *World) {
 	offsetX = hud.XPos
 }
 
+// minimapSize is the width and height of the minimap in cells, each of its
+// pixels covers minimapScale x minimapScale tiles.
+const (
+	minimapSize  = 13
+	minimapScale = 4
+)
+
+func (hud *HUD) renderMinimap(world *World) {
+	level := world.CurrentLevel
+	hud.minimap.Clear()
+	for y := 0; y < level.Rows; y++ {
+		for x := 0; x < level.Columns; x++ {
+			px, py := x/minimapScale, y/minimapScale
+			switch {
+			case x == world.Player.X && y == world.Player.Y:
+				hud.minimap.Set(px, py, Yellow)
+			case level.GetTile(x, y).IsWall() || hud.minimap.At(px, py) == Yellow:
+			case level.VisionMap.VisibilityAt(x, y) == Visible:
+				hud.minimap.Set(px, py, White)
+			case level.VisionMap.VisibilityAt(x, y) == Seen && hud.minimap.At(px, py) != White:
+				hud.minimap.Set(px, py, Grey)
+			}
+		}
+	}
+
+	if err := hud.minimap.Draw(world.Window, hud.XPos, world.Window.Rows-minimapSize); err != nil {
+		log.Println("Couldn't draw minimap", err)
+	}
+}
+
 func (hud *HUD) Render(world *World) {
 	hud.nextFreeRow = 0
 	defer timeMe(time.Now(), "HUD.Render")
@@ -172,4 +228,5 @@ func (hud *HUD) Render(world *World) {
 	hud.renderTurnCount(world)
 	hud.renderEquippedWeapon(world)
 	hud.renderItemDisplay(world)
+	hud.renderMinimap(world)
 }
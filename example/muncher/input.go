@@ -8,3 +8,16 @@ type InputEvent struct {
 	sdl.Event
 	sdl.Keymod
 }
+
+// NewInputEvent takes the modifiers from the key event itself rather than the
+// live keyboard state, so replayed input resolves the same way it was recorded
+func NewInputEvent(event sdl.Event) InputEvent {
+	input := InputEvent{Event: event}
+	switch e := event.(type) {
+	case *sdl.KeyDownEvent:
+		input.Keymod = sdl.Keymod(e.Keysym.Mod)
+	case *sdl.KeyUpEvent:
+		input.Keymod = sdl.Keymod(e.Keysym.Mod)
+	}
+	return input
+}
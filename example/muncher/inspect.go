@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/thomas-holmes/gterm"
-	"github.com/veandco/go-sdl2/sdl"
 )
 
 type InspectionPop struct {
@@ -18,35 +17,21 @@ type InspectionPop struct {
 
 func (pop *InspectionPop) Update(input InputEvent) bool {
 	newX, newY := pop.InspectX, pop.InspectY
-	switch e := input.Event.(type) {
-	case *sdl.KeyDownEvent:
-		switch e.Keysym.Sym {
-		case sdl.K_ESCAPE:
+	if action, ok := input.Action(pop.World.Keymap); ok {
+		if action == Cancel {
 			pop.done = true
 			return true
-		case sdl.K_h:
-			newX = pop.InspectX - 1
-		case sdl.K_j:
-			newY = pop.InspectY + 1
-		case sdl.K_k:
-			newY = pop.InspectY - 1
-		case sdl.K_l:
-			newX = pop.InspectX + 1
-		case sdl.K_b:
-			newX, newY = pop.InspectX-1, pop.InspectY+1
-		case sdl.K_n:
-			newX, newY = pop.InspectX+1, pop.InspectY+1
-		case sdl.K_y:
-			newX, newY = pop.InspectX-1, pop.InspectY-1
-		case sdl.K_u:
-			newX, newY = pop.InspectX+1, pop.InspectY-1
+		}
+		if dx, dy, ok := action.Direction(); ok {
+			newX, newY = pop.InspectX+dx, pop.InspectY+dy
 		}
 	}
 
 	if (newX != pop.InspectX || newY != pop.InspectY) &&
 		(newX > 0 && newX < pop.World.CurrentLevel.Columns) &&
-		(newY > 0 && newY < pop.World.CurrentLevel.Rows) {
-		// Guard against level boundaries
+		(newY > 0 && newY < pop.World.CurrentLevel.Rows) &&
+		pop.World.Camera.Contains(newX, newY) {
+		// Guard against level and camera boundaries
 		pop.InspectX = newX
 		pop.InspectY = newY
 
@@ -111,9 +96,9 @@ func (pop *InspectionPop) RenderCursor(window *gterm.Window) {
 	yellow.A = 200
 	positions := PlotLine(pop.World.Player.X, pop.World.Player.Y, pop.InspectX, pop.InspectY)
 	for _, pos := range positions {
-		pop.World.RenderRuneAt(pos.X, pos.Y, ' ', gterm.NoColor, white)
+		pop.World.DecorateAt(pos.X, pos.Y, gterm.Decoration{Style: gterm.Highlight, Color: white})
 	}
-	pop.World.RenderRuneAt(pop.InspectX, pop.InspectY, ' ', gterm.NoColor, yellow)
+	pop.World.DecorateAt(pop.InspectX, pop.InspectY, gterm.BlinkingCursor(yellow))
 }
 
 func (pop *InspectionPop) Render(window *gterm.Window) {
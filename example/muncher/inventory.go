@@ -30,6 +30,11 @@ func (pop *InventoryPop) tryShowItem(index int) {
 }
 
 func (pop *InventoryPop) Update(input InputEvent) bool {
+	if index, ok := clickedEntry(input, "inventory"); ok {
+		pop.tryShowItem(index)
+		return false
+	}
+
 	switch e := input.Event.(type) {
 	case *sdl.KeyDownEvent:
 		k := e.Keysym.Sym
@@ -52,13 +57,19 @@ func (pop *InventoryPop) renderItem(index int, row int, window *gterm.Window) in
 
 	item := pop.Items[index]
 
-	selectionStr := fmt.Sprintf("%v - ", string('a'+index))
+	selectionStr := fmt.Sprintf("%v - ", string(rune('a'+index)))
 
-	window.PutString(offsetX, offsetY, selectionStr, White)
+	id := entryID("inventory", index)
+	selectionColor := White
+	if window.Hovered() == id {
+		selectionColor = Yellow
+	}
+	window.PutString(offsetX, offsetY, selectionStr, selectionColor)
 
 	name := item.Name
 
 	offsetY += putWrappedText(window, name, offsetX, offsetY, len(selectionStr), 2, pop.W-offsetX+pop.X-1, White)
+	window.AddHitRegion(id, offsetX, row, pop.W-2, offsetY-row)
 	return offsetY
 }
 
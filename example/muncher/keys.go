@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Actions bound in assets/keys.cfg, on top of gterm's movement actions
+const (
+	Ascend         gterm.Action = "Ascend"
+	Descend        gterm.Action = "Descend"
+	PickUp         gterm.Action = "PickUp"
+	OpenInventory  gterm.Action = "OpenInventory"
+	OpenEquipment  gterm.Action = "OpenEquipment"
+	Inspect        gterm.Action = "Inspect"
+	CastSpell      gterm.Action = "CastSpell"
+	ShowLog        gterm.Action = "ShowLog"
+	ShowHelp       gterm.Action = "ShowHelp"
+	Confirm        gterm.Action = "Confirm"
+	Cancel         gterm.Action = "Cancel"
+	NextTarget     gterm.Action = "NextTarget"
+	PreviousTarget gterm.Action = "PreviousTarget"
+)
+
+// hudActions can be done by clicking the HUD, whose hit regions are named after them
+var hudActions = map[gterm.Action]bool{
+	OpenInventory: true,
+	OpenEquipment: true,
+	CastSpell:     true,
+	ShowLog:       true,
+}
+
+// Action resolves a key press to the action it is bound to, or a click on the
+// HUD to the action of the item clicked
+func (input InputEvent) Action(keymap *gterm.Keymap) (gterm.Action, bool) {
+	switch e := input.Event.(type) {
+	case *sdl.KeyDownEvent:
+		return keymap.Resolve(e.Keysym.Sym, input.Keymod)
+	case *gterm.HitEvent:
+		action := gterm.Action(e.ID)
+		if e.Kind == gterm.Click && hudActions[action] {
+			return action, true
+		}
+	}
+	return "", false
+}
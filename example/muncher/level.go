@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"sort"
+
+	"github.com/thomas-holmes/gterm"
 )
 
 type DistanceCandidate struct {
@@ -128,11 +130,23 @@ func LoadCandidateLevel(candidate *CandidateLevel) Level {
 
 	var stairs []Stair
 
+	// Walls facing rooms and corridors are joined with box drawing, beyond the
+	// map is solid rock
+	walls := gterm.Mask(func(x int, y int) bool {
+		if x < 0 || y < 0 || x >= candidate.W || y >= candidate.H {
+			return true
+		}
+		return candidate.tiles[y*candidate.W+x].TileKind == Wall
+	}).Edge()
+
 	for y := 0; y < candidate.H; y++ {
 		for x := 0; x < candidate.W; x++ {
 			tile, cTile := NewTile(x, y), candidate.tiles[y*candidate.W+x]
 			tile.TileKind = cTile.TileKind
 			tile.TileGlyph = TileKindToGlyph(cTile.TileKind)
+			if walls(x, y) {
+				tile.TileGlyph = gterm.SingleLines.Join(walls, x, y)
+			}
 			tile.Item = cTile.Item
 
 			switch tile.TileKind {
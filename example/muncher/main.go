@@ -4,24 +4,25 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math/rand"
+	"os"
 	"path"
+	"time"
 
 	"github.com/thomas-holmes/gterm"
+	"github.com/thomas-holmes/gterm/spectate"
+	"github.com/thomas-holmes/gterm/telnet"
 	"github.com/veandco/go-sdl2/sdl"
 
 	"net/http"
 	_ "net/http/pprof"
 )
 
-var quit = false
-
 func eventActionable(input InputEvent) bool {
-	switch input.Event.(type) {
+	switch e := input.Event.(type) {
 	case *sdl.KeyDownEvent:
 		return true
-	case *sdl.QuitEvent:
-		return true
+	case *gterm.HitEvent:
+		return e.Kind == gterm.Click
 	}
 	return false
 }
@@ -34,19 +35,41 @@ func handleInput(input InputEvent, world *World) {
 			spawnRandomMonster(world)
 		case sdl.K_BACKSLASH:
 			world.ToggleScentOverlay()
+		case sdl.K_F11:
+			mode := gterm.FullscreenDesktop
+			if world.Window.Fullscreen() != gterm.Windowed {
+				mode = gterm.Windowed
+			}
+			if err := world.Window.SetFullscreen(mode); err != nil {
+				log.Println("Failed to toggle fullscreen", err)
+			}
+		case sdl.K_F12:
+			exportHTML(world.Window, "screenshot.html")
 		}
-	case *sdl.QuitEvent:
-		quit = true
+	}
+}
+
+// exportHTML saves what the window shows as a web page
+func exportHTML(window *gterm.Window, name string) {
+	file, err := os.Create(name)
+	if err != nil {
+		log.Println("Failed to create", name, err)
+		return
+	}
+	defer file.Close()
+
+	if err := window.ExportHTML(file); err != nil {
+		log.Println("Failed to export", name, err)
 	}
 }
 
 func spawnRandomMonster(world *World) {
 	for tries := 0; tries < 100; tries++ {
-		x := rand.Intn(world.CurrentLevel.Columns)
-		y := rand.Intn(world.CurrentLevel.Rows)
+		x := world.random.Intn(world.CurrentLevel.Columns)
+		y := world.random.Intn(world.CurrentLevel.Rows)
 
 		if world.CurrentLevel.CanStandOnTile(x, y) {
-			level := rand.Intn(8) + 1
+			level := world.random.Intn(8) + 1
 			monster := NewMonster(x, y, level, level)
 			monster.Name = fmt.Sprintf("A Scary Number %v", level)
 			world.AddEntityToCurrentLevel(&monster)
@@ -56,9 +79,21 @@ func spawnRandomMonster(world *World) {
 }
 
 func main() {
+	flag.Parse()
+
+	if ServeAddr != "" {
+		serve(ServeAddr)
+		return
+	}
+
 	// Disable FPS limit, generally, so I can monitor performance.
 	window := gterm.NewWindow(100, 30, path.Join("assets", "font", "DejaVuSansMono.ttf"), 24, 1.0, !NoVSync)
 
+	window.SetHighDPI(true)
+	if geometry, err := gterm.LoadGeometry(geometryPath); err == nil {
+		window.RestoreGeometry(geometry)
+	}
+
 	if err := window.Init(); err != nil {
 		log.Fatalln("Failed to Init() window", err)
 	}
@@ -69,7 +104,127 @@ func main() {
 
 	window.ShouldRenderFps(true)
 
-	world := NewWorld(window, true, 99)
+	defer announcements(window)()
+
+	keymap, err := gterm.LoadKeymapFile(path.Join("assets", "keys.cfg"))
+	if err != nil {
+		log.Fatalln("Failed to load key bindings", err)
+	}
+
+	settings := &Settings{CameraCentered: true, Seed: 99}
+
+	if ReplayPath != "" {
+		file, err := os.Open(ReplayPath)
+		if err != nil {
+			log.Fatalln("Failed to open replay", err)
+		}
+		mode := gterm.PlaybackRealTime
+		if ReplayFast {
+			mode = gterm.PlaybackFast
+		}
+		if _, err := window.PlayEvents(file, mode); err != nil {
+			log.Fatalln("Failed to load replay", err)
+		}
+		file.Close()
+	}
+
+	if RecordPath != "" {
+		file, err := os.Create(RecordPath)
+		if err != nil {
+			log.Fatalln("Failed to create recording", err)
+		}
+		defer file.Close()
+		window.RecordEvents(file)
+	}
+
+	if SpectateAddr != "" || SpectateJSONAddr != "" {
+		broadcaster := spectate.NewBroadcaster()
+		defer broadcaster.Close()
+		window.AddFrameObserver(broadcaster)
+		serveSpectators(broadcaster, SpectateAddr, spectate.ANSI)
+		serveSpectators(broadcaster, SpectateJSONAddr, spectate.JSON)
+	}
+
+	window.Run(gterm.NewSceneStack(NewTitleScene(window, keymap, settings)), gterm.RunOptions{Mode: gterm.TurnBased})
+
+	if err := gterm.SaveGeometry(geometryPath, window.Geometry()); err != nil {
+		log.Println("Failed to save window geometry", err)
+	}
+}
+
+// serve lets players connect over telnet, each getting their own game
+func serve(addr string) {
+	keymap, err := gterm.LoadKeymapFile(path.Join("assets", "keys.cfg"))
+	if err != nil {
+		log.Fatalln("Failed to load key bindings", err)
+	}
+
+	server := &telnet.Server{
+		Columns:     100,
+		Rows:        30,
+		IdleTimeout: 10 * time.Minute,
+		NewApp: func(session *telnet.Session) gterm.App {
+			log.Println("Player connected from", session.RemoteAddr())
+			// Every player gets their own dungeon
+			settings := &Settings{CameraCentered: true, Seed: uint64(time.Now().UnixNano())}
+			return gterm.NewSceneStack(NewTitleScene(session.Window, keymap, settings))
+		},
+	}
+	log.Println("Serving muncher on", addr)
+	if err := server.ListenAndServe(addr); err != nil {
+		log.Fatalln("Failed to serve", err)
+	}
+}
+
+// announcements sends the game's accessible text where the flags say, the
+// returned func closes it
+func announcements(window *gterm.Window) func() {
+	switch {
+	case Speak:
+		sink := gterm.NewCommandSink("spd-say", "--wait")
+		window.SetTextSink(sink)
+		return func() { sink.Close() }
+	case AnnouncePath == "-":
+		window.SetTextSink(gterm.WriterSink{W: os.Stdout})
+	case AnnouncePath != "":
+		file, err := os.Create(AnnouncePath)
+		if err != nil {
+			log.Fatalln("Failed to create announcement file", err)
+		}
+		window.SetTextSink(gterm.WriterSink{W: file})
+		return func() { file.Close() }
+	}
+	return func() {}
+}
+
+// serveSpectators lets people watch the game on addr, if it's set
+func serveSpectators(broadcaster *spectate.Broadcaster, addr string, format spectate.Format) {
+	if addr == "" {
+		return
+	}
+	go func() {
+		if err := broadcaster.ListenAndServe(addr, format); err != nil {
+			log.Println("Failed to serve spectators on", addr, err)
+		}
+	}()
+}
+
+// geometryPath is where the window's position and size are kept between runs
+const geometryPath = "window.json"
+
+// Settings are chosen from the title screen and kept between games
+type Settings struct {
+	CameraCentered bool
+	Seed           uint64
+	// Colors indexes colorFilters
+	Colors int
+}
+
+// NewGame builds a fresh world and player, each game gets the next seed
+func NewGame(window *gterm.Window, keymap *gterm.Keymap, settings *Settings) *GameScene {
+	world := NewWorld(window, settings.CameraCentered, settings.Seed)
+	settings.Seed++
+	world.Keymap = keymap
 	{
 		// TODO: Roll this up into some kind of registering a system function on the world
 		combat := CombatSystem{World: world}
@@ -97,39 +252,110 @@ func main() {
 
 	hud := NewHud(&player, world, 60, 0)
 
-	for !quit && !world.QuitGame {
+	return &GameScene{World: world, HUD: hud, settings: settings}
+}
 
-		inputEvent := InputEvent{Event: sdl.PollEvent(), Keymod: sdl.GetModState()}
-		window.ClearWindow()
-		if world.turnCount == 0 || eventActionable(inputEvent) {
+// GameScene plays a game until the player quits or dies, then goes back to the title
+type GameScene struct {
+	World *World
+	HUD   *HUD
 
-			handleInput(inputEvent, world)
+	settings *Settings
+	scenes   *gterm.SceneStack
+}
 
-			// TODO: Consider moving this into the world update loop?
-			world.AddInput(inputEvent)
+func (game *GameScene) Enter(scenes *gterm.SceneStack) {
+	game.scenes = scenes
+}
 
-			updateLoops := 0
-			for !world.Update() && !world.GameOver {
-				updateLoops++
-			}
-			log.Printf("Ran %v update loops", updateLoops)
-		}
-		if world.Animating() {
-			world.UpdateAnimations()
-		}
+func (game *GameScene) Exit() {
+	game.World.Window.ClearEffects()
+	game.World.Window.ClearFloatingGlyphs()
+}
+
+func (game *GameScene) HandleEvent(event sdl.Event) {
+	if e, ok := event.(*sdl.KeyDownEvent); ok && e.Keysym.Sym == sdl.K_F10 {
+		game.readHUD()
+		return
+	}
+	input := NewInputEvent(event)
+	if eventActionable(input) {
+		game.step(input)
+	}
+}
+
+// readHUD reads the player's stats out through the window's text sink
+func (game *GameScene) readHUD() {
+	window := game.World.Window
+	hud := game.HUD
+	if err := window.AnnounceRegion(hud.XPos, hud.YPos, window.Columns-hud.XPos, window.Rows-hud.YPos); err != nil {
+		log.Println("Failed to read the HUD", err)
+	}
+}
+
+func (game *GameScene) step(input InputEvent) {
+	world := game.World
 
-		world.Render()
+	handleInput(input, world)
 
-		hud.Render(world)
+	// TODO: Consider moving this into the world update loop?
+	world.AddInput(input)
 
-		window.Refresh()
+	updateLoops := 0
+	for !world.Update() && !world.GameOver {
+		updateLoops++
+	}
+	log.Printf("Ran %v update loops", updateLoops)
+}
+
+func (game *GameScene) Update(delta uint32) {
+	if game.World.QuitGame {
+		game.scenes.Replace(NewTitleScene(game.World.Window, game.World.Keymap, game.settings))
+		return
+	}
+	if game.World.turnCount == 0 {
+		game.step(InputEvent{})
+	}
+	if game.World.Animating() {
+		game.World.UpdateAnimations()
 	}
 }
 
+func (game *GameScene) Render(window *gterm.Window) {
+	game.World.Render()
+
+	game.HUD.Render(game.World)
+}
+
+func (game *GameScene) Animating() bool {
+	return game.World.Animating()
+}
+
 var NoVSync = true
 
+// RecordPath and ReplayPath are where to record input to and play it back from
+var RecordPath, ReplayPath string
+var ReplayFast bool
+
+// ServeAddr is where to listen for telnet players instead of opening a window
+var ServeAddr string
+
+// SpectateAddr and SpectateJSONAddr are where spectators can watch as ANSI and JSON deltas
+var SpectateAddr, SpectateJSONAddr string
+
+// AnnouncePath is where to write announcements for screen readers, Speak says them with speech-dispatcher
+var AnnouncePath string
+var Speak bool
+
 func init() {
 	go http.ListenAndServe("localhost:6060", nil)
 	flag.BoolVar(&NoVSync, "no-vsync", false, "disable vsync")
-	flag.Parse()
+	flag.StringVar(&RecordPath, "record", "", "record input to `file`")
+	flag.StringVar(&ReplayPath, "replay", "", "play back input recorded in `file`")
+	flag.BoolVar(&ReplayFast, "replay-fast", false, "play back as fast as possible rather than in real time")
+	flag.StringVar(&ServeAddr, "serve", "", "serve games over telnet on `address`, like :2323")
+	flag.StringVar(&SpectateAddr, "spectate", "", "let spectators watch in a terminal by connecting to `address`")
+	flag.StringVar(&SpectateJSONAddr, "spectate-json", "", "stream JSON cell deltas to spectators connecting to `address`")
+	flag.StringVar(&AnnouncePath, "announce", "", "write game messages as text to `file`, - for stdout")
+	flag.BoolVar(&Speak, "speak", false, "say game messages with speech-dispatcher's spd-say")
 }
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/thomas-holmes/gterm"
 )
 
@@ -22,3 +26,18 @@ type Menu interface {
 	Render(window *gterm.Window)
 	Done() bool
 }
+
+// entryID names the hit region of a list entry, like inventory:2
+func entryID(list string, index int) string {
+	return fmt.Sprintf("%v:%v", list, index)
+}
+
+// clickedEntry reports which entry of list was clicked, if input is a click on one
+func clickedEntry(input InputEvent, list string) (int, bool) {
+	hit, ok := input.Event.(*gterm.HitEvent)
+	if !ok || hit.Kind != gterm.Click || !strings.HasPrefix(hit.ID, list+":") {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimPrefix(hit.ID, list+":"))
+	return index, err == nil
+}
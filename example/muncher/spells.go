@@ -65,6 +65,11 @@ func (pop *SpellPop) castSpell(index int) {
 }
 
 func (pop *SpellPop) Update(input InputEvent) bool {
+	if index, ok := clickedEntry(input, "spell"); ok {
+		pop.castSpell(index)
+		return true
+	}
+
 	switch e := input.Event.(type) {
 	case *sdl.KeyDownEvent:
 		k := e.Keysym.Sym
@@ -91,13 +96,19 @@ func (pop SpellPop) renderItem(index int, row int, window *gterm.Window) int {
 	if pop.World.Player.CanCast(spell) {
 		itemColor = White
 	}
-	selectionStr := fmt.Sprintf("%v - ", string('a'+index))
+	selectionStr := fmt.Sprintf("%v - ", string(rune('a'+index)))
 
-	window.PutString(offsetX, offsetY, selectionStr, itemColor)
+	id := entryID("spell", index)
+	selectionColor := itemColor
+	if window.Hovered() == id {
+		selectionColor = Yellow
+	}
+	window.PutString(offsetX, offsetY, selectionStr, selectionColor)
 
 	name := spell.Name
 
 	offsetY += putWrappedText(window, name, offsetX, offsetY, len(selectionStr), 2, pop.W-offsetX+pop.X-1, itemColor)
+	window.AddHitRegion(id, offsetX, row, pop.W-2, offsetY-row)
 	return offsetY
 }
 
@@ -169,38 +180,24 @@ func (pop *SpellTargeting) Update(input InputEvent) bool {
 	player := pop.World.Player
 	pop.setInitialState()
 	newX, newY := pop.TargetX, pop.TargetY
-	switch e := input.Event.(type) {
-	case *sdl.KeyDownEvent:
-		switch e.Keysym.Sym {
-		case sdl.K_RETURN:
+	if action, ok := input.Action(pop.World.Keymap); ok {
+		if dx, dy, ok := action.Direction(); ok {
+			newX, newY = pop.TargetX+dx, pop.TargetY+dy
+		}
+		switch action {
+		case Confirm:
 			if pop.distance <= pop.Spell.Range {
 				pop.done = true
 				pop.World.Player.CastSpell(pop.Spell, pop.World, pop.TargetX, pop.TargetY)
 			} else {
 				fmt.Println("Can't cast, out of range.")
 			}
-		case sdl.K_ESCAPE:
+		case Cancel:
 			pop.done = true
-		case sdl.K_h:
-			newX = pop.TargetX - 1
-		case sdl.K_j:
-			newY = pop.TargetY + 1
-		case sdl.K_k:
-			newY = pop.TargetY - 1
-		case sdl.K_l:
-			newX = pop.TargetX + 1
-		case sdl.K_b:
-			newX, newY = pop.TargetX-1, pop.TargetY+1
-		case sdl.K_n:
-			newX, newY = pop.TargetX+1, pop.TargetY+1
-		case sdl.K_y:
-			newX, newY = pop.TargetX-1, pop.TargetY-1
-		case sdl.K_u:
-			newX, newY = pop.TargetX+1, pop.TargetY-1
-		case sdl.K_EQUALS:
+		case NextTarget:
 			pop.creatureIndex = (pop.creatureIndex + 1) % len(pop.creatures)
 			newX, newY = pop.creatures[pop.creatureIndex].X, pop.creatures[pop.creatureIndex].Y
-		case sdl.K_MINUS:
+		case PreviousTarget:
 			pop.creatureIndex = (pop.creatureIndex - 1)
 			if pop.creatureIndex < 0 {
 				pop.creatureIndex = len(pop.creatures) - 1
@@ -244,12 +241,19 @@ func (pop SpellTargeting) renderSquareCursor() {
 	cursorColor.A = 125
 	for y := minY; y < maxY+1; y++ {
 		for x := minX; x < maxX+1; x++ {
-			pop.World.RenderRuneAt(x, y, ' ', gterm.NoColor, cursorColor)
+			pop.World.DecorateAt(x, y, gterm.Decoration{Style: gterm.Highlight, Color: cursorColor})
 		}
 	}
 
-	cursorColor.A = 200
-	pop.World.RenderRuneAt(pop.TargetX, pop.TargetY, ' ', gterm.NoColor, cursorColor)
+	pop.renderTargetCursor()
+}
+
+// renderTargetCursor brackets the targeted cell so whatever is standing there stays readable
+func (pop SpellTargeting) renderTargetCursor() {
+	cursorColor := pop.cursorColor
+	cursorColor.A = 255
+	pop.World.DecorateAt(pop.TargetX, pop.TargetY, gterm.Decoration{Style: gterm.CornerBrackets, Color: cursorColor})
+	pop.World.DecorateAt(pop.TargetX, pop.TargetY, gterm.BlinkingCursor(cursorColor))
 }
 
 func conePositions(pX, pY, x0, y0, size int) []Position {
@@ -296,22 +300,20 @@ func (pop SpellTargeting) renderConeCursor() {
 
 	cursorColor.A = 125
 	for _, pos := range conePositions(player.X, player.Y, pop.TargetX, pop.TargetY, spell.Size) {
-		pop.World.RenderRuneAt(pos.X, pos.Y, ' ', gterm.NoColor, cursorColor)
+		pop.World.DecorateAt(pos.X, pos.Y, gterm.Decoration{Style: gterm.Highlight, Color: cursorColor})
 	}
 
-	cursorColor.A = 200
-	pop.World.RenderRuneAt(pop.TargetX, pop.TargetY, ' ', gterm.NoColor, cursorColor)
-
+	pop.renderTargetCursor()
 }
 
 func (pop SpellTargeting) Render(window *gterm.Window) {
 	lineColor := pop.lineColor
 
-	lineColor.A = 50
+	lineColor.A = 150
 
 	positions := PlotLine(pop.World.Player.X, pop.World.Player.Y, pop.TargetX, pop.TargetY)
 	for _, pos := range positions {
-		pop.World.RenderRuneAt(pos.X, pos.Y, ' ', gterm.NoColor, lineColor)
+		pop.World.DecorateAt(pos.X, pos.Y, gterm.Decoration{Style: gterm.Underline, Color: lineColor})
 	}
 
 	switch pop.Spell.Shape {
@@ -44,6 +44,9 @@ func NewTile(x int, y int) Tile {
 	}
 }
 
+// SeenLight dims tiles remembered from earlier but out of sight now
+var SeenLight = sdl.Color{R: 128, G: 128, B: 128, A: 255}
+
 type Tile struct {
 	X int
 	Y int
@@ -66,6 +69,10 @@ func (tile *Tile) Render(world *World, visibility Visibility) {
 		return
 	}
 
+	if visibility == Seen {
+		world.SetLightAt(tile.X, tile.Y, SeenLight)
+	}
+
 	if tile.Creature != nil && visibility == Visible {
 		tile.Creature.Render(world)
 	} else {
@@ -85,11 +92,5 @@ func (tile Tile) RenderBackground(world *World, visibility Visibility) {
 		color = tile.Color
 	}
 
-	if visibility == Seen {
-		color.R /= 2
-		color.G /= 2
-		color.B /= 2
-	}
-
 	world.RenderRuneAt(tile.X, tile.Y, glyph, color, gterm.NoColor)
 }
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"log"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// choiceList is a vertical list of choices moved through with the movement keys
+type choiceList struct {
+	Choices  []string
+	Selected int
+}
+
+// Update moves the selection and reports whether the player confirmed it or backed out
+func (list *choiceList) Update(input InputEvent, keymap *gterm.Keymap) (action gterm.Action, ok bool) {
+	action, ok = input.Action(keymap)
+	if !ok {
+		return "", false
+	}
+	switch action {
+	case gterm.MoveNorth:
+		list.Selected = (list.Selected + len(list.Choices) - 1) % len(list.Choices)
+	case gterm.MoveSouth:
+		list.Selected = (list.Selected + 1) % len(list.Choices)
+	case Confirm, Cancel:
+		return action, true
+	}
+	return "", false
+}
+
+func (list *choiceList) Render(window *gterm.Window, x int, y int, width int) {
+	for i, choice := range list.Choices {
+		window.PutString(x, y+i*2, choice, White)
+		if i == list.Selected {
+			highlight := Yellow
+			highlight.A = 60
+			for col := x - 1; col < x+width; col++ {
+				window.Decorate(col, y+i*2, gterm.Decoration{Style: gterm.Highlight, Color: highlight})
+			}
+			window.Decorate(x-2, y+i*2, gterm.BlinkingCursor(Yellow))
+		}
+	}
+}
+
+const (
+	newGameChoice = "New Game"
+	optionsChoice = "Options"
+	quitChoice    = "Quit"
+)
+
+// TitleScene is the first thing the player sees and where they return after a game
+type TitleScene struct {
+	window   *gterm.Window
+	keymap   *gterm.Keymap
+	settings *Settings
+	scenes   *gterm.SceneStack
+
+	font    gterm.BigFont
+	choices choiceList
+}
+
+func NewTitleScene(window *gterm.Window, keymap *gterm.Keymap, settings *Settings) *TitleScene {
+	return &TitleScene{
+		window:   window,
+		keymap:   keymap,
+		settings: settings,
+		choices:  choiceList{Choices: []string{newGameChoice, optionsChoice, quitChoice}},
+	}
+}
+
+func (title *TitleScene) Enter(scenes *gterm.SceneStack) {
+	title.scenes = scenes
+	if font, err := title.window.ScaledFont(3, 3); err == nil {
+		title.font = font
+	} else {
+		log.Println("Couldn't scale font for the title", err)
+	}
+}
+
+func (title *TitleScene) Exit() {}
+
+func (title *TitleScene) HandleEvent(event sdl.Event) {
+	action, ok := title.choices.Update(NewInputEvent(event), title.keymap)
+	if !ok {
+		return
+	}
+	if action == Cancel {
+		title.scenes.Quit()
+		return
+	}
+
+	switch title.choices.Choices[title.choices.Selected] {
+	case newGameChoice:
+		title.scenes.Replace(NewGame(title.window, title.keymap, title.settings))
+	case optionsChoice:
+		title.scenes.Push(NewOptionsScene(title.window, title.keymap, title.settings))
+	case quitChoice:
+		title.scenes.Quit()
+	}
+}
+
+func (title *TitleScene) Update(delta uint32) {}
+
+func (title *TitleScene) Render(window *gterm.Window) {
+	const name = "MUNCHER"
+	if title.font != nil {
+		x := (window.Columns - gterm.BigTextWidth(name, title.font)) / 2
+		if err := window.PutBigText(x, 3, name, title.font, Yellow); err != nil {
+			log.Println("Failed to render title", err)
+		}
+	} else {
+		window.PutString((window.Columns-len(name))/2, 5, name, Yellow)
+	}
+
+	title.choices.Render(window, (window.Columns-10)/2, 12, 10)
+}
+
+// colorFilters are the colour options, for players who can't tell muncher's reds and greens apart
+var colorFilters = []struct {
+	Name   string
+	Filter gterm.ColorFilter
+}{
+	{"Normal", nil},
+	{"High contrast", gterm.HighContrast{}},
+	{"Protanopia", gterm.CorrectColorBlindness{Kind: gterm.Protanopia}},
+	{"Deuteranopia", gterm.CorrectColorBlindness{Kind: gterm.Deuteranopia}},
+	{"Tritanopia", gterm.CorrectColorBlindness{Kind: gterm.Tritanopia}},
+}
+
+// OptionsScene pops up over the title to change settings
+type OptionsScene struct {
+	window   *gterm.Window
+	keymap   *gterm.Keymap
+	settings *Settings
+	scenes   *gterm.SceneStack
+
+	choices choiceList
+
+	PopMenu
+}
+
+func NewOptionsScene(window *gterm.Window, keymap *gterm.Keymap, settings *Settings) *OptionsScene {
+	return &OptionsScene{
+		window:   window,
+		keymap:   keymap,
+		settings: settings,
+		choices:  choiceList{Choices: make([]string, 4)},
+		PopMenu:  PopMenu{X: (window.Columns - 38) / 2, Y: 10, W: 38, H: 11},
+	}
+}
+
+func (options *OptionsScene) Enter(scenes *gterm.SceneStack) {
+	options.scenes = scenes
+}
+
+func (options *OptionsScene) Exit() {}
+
+// Transparent keeps the title showing around the popup
+func (options *OptionsScene) Transparent() bool {
+	return true
+}
+
+func (options *OptionsScene) HandleEvent(event sdl.Event) {
+	action, ok := options.choices.Update(NewInputEvent(event), options.keymap)
+	if !ok {
+		return
+	}
+	if action == Cancel {
+		options.scenes.Pop()
+		return
+	}
+
+	switch options.choices.Selected {
+	case 0:
+		mode := gterm.FullscreenDesktop
+		if options.window.Fullscreen() != gterm.Windowed {
+			mode = gterm.Windowed
+		}
+		if err := options.window.SetFullscreen(mode); err != nil {
+			log.Println("Failed to toggle fullscreen", err)
+		}
+	case 1:
+		options.settings.CameraCentered = !options.settings.CameraCentered
+	case 2:
+		options.settings.Colors = (options.settings.Colors + 1) % len(colorFilters)
+		options.window.SetColorFilter(colorFilters[options.settings.Colors].Filter)
+	case 3:
+		options.scenes.Pop()
+	}
+}
+
+func (options *OptionsScene) Update(delta uint32) {}
+
+func onOff(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
+
+func (options *OptionsScene) Render(window *gterm.Window) {
+	if err := window.ClearRegion(options.X, options.Y, options.W, options.H); err != nil {
+		log.Println("Failed to clear options region", err)
+	}
+
+	options.choices.Choices[0] = "Fullscreen      " + onOff(options.window.Fullscreen() != gterm.Windowed)
+	options.choices.Choices[1] = "Centered camera " + onOff(options.settings.CameraCentered)
+	options.choices.Choices[2] = "Colours         " + colorFilters[options.settings.Colors].Name
+	options.choices.Choices[3] = "Back"
+	options.choices.Render(window, options.X+4, options.Y+2, options.W-8)
+}
@@ -24,6 +24,8 @@ type World struct {
 	turnCount uint64
 
 	rng *pcg.PCG64
+	// random picks monsters, each game has its own so sessions don't share it
+	random *rand.Rand
 
 	Player *Creature
 
@@ -37,12 +39,9 @@ type World struct {
 	CurrentTickDelta   uint32
 
 	CameraCentered bool
-	CameraWidth    int
-	CameraHeight   int
-	CameraOffsetX  int
-	CameraOffsetY  int
-	CameraX        int
-	CameraY        int
+	Camera         *gterm.Viewport
+
+	Keymap *gterm.Keymap
 
 	nextID int
 
@@ -95,7 +94,7 @@ func (world *World) addInitialMonsters(level *Level) {
 		y := int(world.rng.Bounded(uint64(level.Rows)))
 
 		if level.CanStandOnTile(x, y) {
-			creatureLevel := rand.Intn(8) + 1
+			creatureLevel := world.random.Intn(8) + 1
 			monster := NewMonster(x, y, creatureLevel, creatureLevel)
 			monster.Name = fmt.Sprintf("A Scary Number %v", creatureLevel)
 			world.AddEntity(&monster, level)
@@ -121,9 +120,9 @@ func (world *World) AddLevelFromCandidate(level *CandidateLevel) {
 func (world *World) addPlayer(player *Creature, level *Level) {
 	world.Player = player
 
+	world.Camera.SetBounds(level.Columns, level.Rows)
 	if world.CameraCentered {
-		world.CameraX = player.X
-		world.CameraY = player.Y
+		world.Camera.CenterOn(player.X, player.Y)
 	}
 
 	level.VisionMap.UpdateVision(world.Player.VisionDistance, world)
@@ -175,14 +174,47 @@ func (world *World) AddEntity(e Entity, level *Level) {
 }
 
 func (world *World) RenderRuneAt(x int, y int, out rune, fColor sdl.Color, bColor sdl.Color) {
-	err := world.Window.PutRune(x-world.CameraX+world.CameraOffsetX, y-world.CameraY+world.CameraOffsetY, out, fColor, bColor)
+	col, row, ok := world.Camera.WorldToScreen(x, y)
+	if !ok {
+		return
+	}
+	err := world.Window.PutRune(col, row, out, fColor, bColor)
 	if err != nil {
 		log.Printf("Out of bounds %s", err)
 	}
 }
 
+func (world *World) SetLightAt(x int, y int, light sdl.Color) {
+	col, row, ok := world.Camera.WorldToScreen(x, y)
+	if !ok {
+		return
+	}
+	if err := world.Window.SetLight(col, row, light); err != nil {
+		log.Printf("Out of bounds %s", err)
+	}
+}
+
+// ScreenPosition is the window cell (x, y) is drawn in, even when the camera can't see it
+func (world *World) ScreenPosition(x int, y int) (float64, float64) {
+	return float64(world.Camera.ScreenX + x - world.Camera.X), float64(world.Camera.ScreenY + y - world.Camera.Y)
+}
+
+func (world *World) DecorateAt(x int, y int, decoration gterm.Decoration) {
+	col, row, ok := world.Camera.WorldToScreen(x, y)
+	if !ok {
+		return
+	}
+	if err := world.Window.Decorate(col, row, decoration); err != nil {
+		log.Printf("Out of bounds %s", err)
+	}
+}
+
 func (world *World) RenderStringAt(x int, y int, out string, color sdl.Color) {
-	err := world.Window.PutString(x-world.CameraX+world.CameraOffsetX, y-world.CameraY+world.CameraOffsetY, out, color)
+	col, row, ok := world.Camera.WorldToScreen(x, y)
+	if !ok {
+		return
+	}
+	err := world.Window.PutString(col, row, out, color)
 	if err != nil {
 		log.Printf("Out of bounds %s", err)
 	}
@@ -299,16 +331,12 @@ func (world *World) Update() bool {
 }
 
 func (world *World) UpdateCamera() {
+	world.Camera.SetBounds(world.CurrentLevel.Columns, world.CurrentLevel.Rows)
 	if world.CameraCentered {
-		world.CameraOffsetX = 30
-		world.CameraOffsetY = 15
-		world.CameraX = world.Player.X
-		world.CameraY = world.Player.Y
+		world.Camera.Follow(world.Player.X, world.Player.Y)
 	} else {
-		world.CameraOffsetX = 0
-		world.CameraOffsetY = 0
-		world.CameraX = 0
-		world.CameraY = 0
+		world.Camera.X = 0
+		world.Camera.Y = 0
 	}
 }
 
@@ -316,14 +344,7 @@ func (world *World) UpdateCamera() {
 func (world *World) Render() {
 	world.UpdateCamera()
 	defer timeMe(time.Now(), "World.Render.TileLoop")
-	var minX, minY, maxX, maxY int
-	if world.CameraCentered {
-		minY, maxY = max(0, world.CameraY-(world.CameraHeight/2)), min(world.CurrentLevel.Rows, world.CameraY+(world.CameraHeight/2))
-		minX, maxX = max(0, world.CameraX-(world.CameraWidth/2)), min(world.CurrentLevel.Columns, world.CameraX+(world.CameraWidth/2))
-	} else {
-		minY, maxY = 0, world.CurrentLevel.Rows
-		minX, maxX = 0, world.CurrentLevel.Columns
-	}
+	minX, minY, maxX, maxY := world.Camera.VisibleBounds()
 	for row := minY; row < maxY; row++ {
 		for col := minX; col < maxX; col++ {
 			tile := world.CurrentLevel.GetTile(col, row)
@@ -480,7 +501,20 @@ func (world *World) AddAnimation(a Animation) {
 }
 
 func (world *World) ShowEndGameMenu() {
-	pop := NewEndGameMenu(10, 5, 40, 6, Red, "YOU ARE VERY DEAD", "I AM SO SORRY :(")
+	// Keep the dungeon as it was when the player died, before the menu covers it
+	exportHTML(world.Window, "morgue.html")
+
+	var pop EndGameMenu
+	if font, err := world.Window.ScaledFont(3, 3); err == nil {
+		pop = NewEndGameMenu(8, 3, 40, 6, Red, "I AM SO SORRY :(", "", "Press Enter to return to the title")
+		pop.SetTitle("YOU ARE\nVERY DEAD", font)
+	} else {
+		log.Println("Couldn't scale font for the end game title", err)
+		pop = NewEndGameMenu(10, 5, 40, 6, Red, "YOU ARE VERY DEAD", "I AM SO SORRY :(", "", "Press Enter to return to the title")
+	}
+	pop.world = world
+	world.Window.AddEffect(&gterm.ShakeEffect{Magnitude: 6}, 400, false)
+	world.Window.AddEffect(&gterm.DesaturateEffect{Amount: 0.8}, 1500, true)
 	world.GameOver = true
 	world.Broadcast(ShowMenu, ShowMenuMessage{Menu: &pop})
 }
@@ -512,6 +546,7 @@ func (world *World) Notify(message Message, data interface{}) {
 			world.CurrentLevel = d.DestLevel
 			world.LevelChanged = true
 			world.AddEntityToCurrentLevel(world.Player)
+			world.Window.AddEffect(&gterm.FadeEffect{Color: sdl.Color{A: 255}, From: true}, 500, false)
 		}
 	case ShowMenu:
 		if d, ok := data.(ShowMenuMessage); ok {
@@ -553,13 +588,11 @@ func NewWorld(window *gterm.Window, centered bool, seed uint64) *World {
 		Window:         window,
 		CameraCentered: centered,
 		MaxDepth:       15,
-		CameraX:        0,
-		CameraY:        0,
 		// TODO: Width/Height should probably be some function of the window dimensions
-		CameraWidth:        56,
-		CameraHeight:       25,
+		Camera:             gterm.NewViewport(0, 0, 56, window.Rows-5),
 		CurrentUpdateTicks: sdl.GetTicks(),
 		rng:                pcg.NewPCG64(),
+		random:             rand.New(rand.NewSource(int64(seed))),
 	}
 
 	world.rng.Seed(seed, DefaultSeq, seed*seed, DefaultSeq+1)
@@ -0,0 +1,201 @@
+package gterm
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Easing maps linear progress t, from 0 to 1, onto eased progress
+type Easing func(t float64) float64
+
+// Linear moves at a constant speed
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slow and speeds up
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts fast and slows down
+func EaseOutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOutQuad speeds up then slows down
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseOutBack overshoots the end a little before settling, good for knockback
+func EaseOutBack(t float64) float64 {
+	const overshoot = 1.70158
+	t--
+	return t*t*((overshoot+1)*t+overshoot) + 1
+}
+
+// Tween eases between two positions over Duration milliseconds from Start
+type Tween struct {
+	FromX    float64
+	FromY    float64
+	ToX      float64
+	ToY      float64
+	Start    uint32
+	Duration uint32
+	Easing   Easing
+}
+
+// At returns the position at ticks and whether the tween has finished
+func (tween Tween) At(ticks uint32) (x float64, y float64, done bool) {
+	if ticks < tween.Start {
+		return tween.FromX, tween.FromY, false
+	}
+	elapsed := ticks - tween.Start
+	if elapsed >= tween.Duration {
+		return tween.ToX, tween.ToY, true
+	}
+
+	easing := tween.Easing
+	if easing == nil {
+		easing = Linear
+	}
+	t := easing(float64(elapsed) / float64(tween.Duration))
+	return tween.FromX + (tween.ToX-tween.FromX)*t, tween.FromY + (tween.ToY-tween.FromY)*t, false
+}
+
+// FloatingGlyph is a glyph drawn above the grid at a fractional cell position.
+// It is independent of the cells underneath, clearing or putting cells never
+// touches it.
+type FloatingGlyph struct {
+	// X and Y are in cells, 1.5 is half way between the second and third column
+	X float64
+	Y float64
+	// OffsetX and OffsetY nudge the glyph by whole pixels
+	OffsetX int
+	OffsetY int
+
+	Glyph  rune
+	FColor sdl.Color
+	BColor sdl.Color
+
+	tween  *Tween
+	window *Window
+}
+
+// MoveTo tweens the glyph from where it is now to (x, y) over duration milliseconds
+func (glyph *FloatingGlyph) MoveTo(x float64, y float64, duration uint32, easing Easing) {
+	glyph.tween = &Tween{
+		FromX:    glyph.X,
+		FromY:    glyph.Y,
+		ToX:      x,
+		ToY:      y,
+		Start:    sdl.GetTicks(),
+		Duration: duration,
+		Easing:   easing,
+	}
+}
+
+// Moving reports whether the glyph is part way through a MoveTo
+func (glyph *FloatingGlyph) Moving() bool {
+	return glyph.tween != nil
+}
+
+// floatingState is everything about a floating glyph that shows on screen
+type floatingState struct {
+	x, y             float64
+	offsetX, offsetY int
+	glyph            rune
+	fColor, bColor   sdl.Color
+}
+
+func (glyph *FloatingGlyph) state() floatingState {
+	return floatingState{
+		x:       glyph.X,
+		y:       glyph.Y,
+		offsetX: glyph.OffsetX,
+		offsetY: glyph.OffsetY,
+		glyph:   glyph.Glyph,
+		fColor:  glyph.FColor,
+		bColor:  glyph.BColor,
+	}
+}
+
+// AddFloatingGlyph puts a glyph on the floating layer at (x, y). Glyphs added
+// later are drawn on top.
+func (window *Window) AddFloatingGlyph(glyph rune, fColor sdl.Color, bColor sdl.Color, x float64, y float64) *FloatingGlyph {
+	floating := &FloatingGlyph{X: x, Y: y, Glyph: glyph, FColor: fColor, BColor: bColor, window: window}
+	window.floating = append(window.floating, floating)
+	return floating
+}
+
+// RemoveFloatingGlyph takes a glyph off the floating layer
+func (window *Window) RemoveFloatingGlyph(glyph *FloatingGlyph) {
+	for i, g := range window.floating {
+		if g == glyph {
+			window.floating = append(window.floating[:i], window.floating[i+1:]...)
+			return
+		}
+	}
+}
+
+// Remove takes the glyph off the floating layer of the window it was added to
+func (glyph *FloatingGlyph) Remove() {
+	glyph.window.RemoveFloatingGlyph(glyph)
+}
+
+// ClearFloatingGlyphs empties the floating layer
+func (window *Window) ClearFloatingGlyphs() {
+	window.floating = window.floating[:0]
+}
+
+// advanceFloating moves tweening glyphs on and reports whether any are still moving
+func (window *Window) advanceFloating() bool {
+	ticks := sdl.GetTicks()
+	moving := false
+	for _, glyph := range window.floating {
+		if glyph.tween == nil {
+			continue
+		}
+		var done bool
+		glyph.X, glyph.Y, done = glyph.tween.At(ticks)
+		if done {
+			glyph.tween = nil
+		} else {
+			moving = true
+		}
+	}
+	return moving
+}
+
+// floatingChanged reports whether the floating layer looks different from when it was last drawn
+func (window *Window) floatingChanged() bool {
+	if len(window.floating) != len(window.floatingDrawn) {
+		return true
+	}
+	for i, glyph := range window.floating {
+		if glyph.state() != window.floatingDrawn[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func (window *Window) renderFloating() error {
+	window.floatingDrawn = window.floatingDrawn[:0]
+	for _, glyph := range window.floating {
+		window.floatingDrawn = append(window.floatingDrawn, glyph.state())
+
+		destX := int(math.Floor(glyph.X*float64(window.DisplayWPixel))) + glyph.OffsetX + window.offsetX
+		destY := int(math.Floor(glyph.Y*float64(window.DisplayHPixel))) + glyph.OffsetY + window.offsetY
+		destRect := sdl.Rect{X: int32(destX), Y: int32(destY), W: int32(window.DisplayWPixel), H: int32(window.DisplayHPixel)}
+		if err := window.renderGlyph(renderItem{Glyph: glyph.Glyph, FColor: glyph.FColor}, glyph.BColor, destRect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gterm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEasingEndpoints(t *testing.T) {
+	easings := map[string]Easing{
+		"Linear":        Linear,
+		"EaseInQuad":    EaseInQuad,
+		"EaseOutQuad":   EaseOutQuad,
+		"EaseInOutQuad": EaseInOutQuad,
+		"EaseOutBack":   EaseOutBack,
+	}
+	for name, easing := range easings {
+		if start := easing(0); math.Abs(start) > 1e-9 {
+			t.Errorf("%v(0) = %v, expected 0", name, start)
+		}
+		if end := easing(1); math.Abs(end-1) > 1e-9 {
+			t.Errorf("%v(1) = %v, expected 1", name, end)
+		}
+	}
+}
+
+func TestTweenAt(t *testing.T) {
+	tween := Tween{FromX: 2, FromY: 4, ToX: 6, ToY: 0, Start: 100, Duration: 200}
+
+	cases := []struct {
+		ticks uint32
+		x, y  float64
+		done  bool
+	}{
+		{ticks: 50, x: 2, y: 4},
+		{ticks: 100, x: 2, y: 4},
+		{ticks: 200, x: 4, y: 2},
+		{ticks: 250, x: 5, y: 1},
+		{ticks: 300, x: 6, y: 0, done: true},
+		{ticks: 1000, x: 6, y: 0, done: true},
+	}
+	for _, c := range cases {
+		x, y, done := tween.At(c.ticks)
+		if x != c.x || y != c.y || done != c.done {
+			t.Errorf("At(%v) = (%v, %v, %v), expected (%v, %v, %v)", c.ticks, x, y, done, c.x, c.y, c.done)
+		}
+	}
+}
+
+func TestTweenEasing(t *testing.T) {
+	tween := Tween{ToX: 10, Duration: 100, Easing: EaseInQuad}
+	if x, _, _ := tween.At(50); x != 2.5 {
+		t.Errorf("Expected eased x of 2.5 half way, got %v", x)
+	}
+}
@@ -0,0 +1,170 @@
+package gterm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FontInfo describes a CP437 sprite sheet a window can switch to
+type FontInfo struct {
+	Name   string
+	Path   string
+	Width  int
+	Height int
+}
+
+// FontRegistry is the list of fonts available to a window, in config order
+type FontRegistry struct {
+	Fonts []FontInfo
+}
+
+var fontSizePattern = regexp.MustCompile(`(\d+)x(\d+)`)
+
+// LoadFontConfig reads a font config (like example/atlas/fonts/_config.xt) from
+// path. Sheets are looked up next to the config and their cell size comes from
+// the "WxH" part of the sheet name.
+func LoadFontConfig(path string) (*FontRegistry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseFontConfig(file, filepath.Dir(path))
+}
+
+// ParseFontConfig reads a font config, resolving sheet names relative to dir.
+// Each line is a quoted set name followed by the GUI sheet, the art sheet and an
+// availability flag, anything after // is a comment. The first set is always
+// available.
+func ParseFontConfig(r io.Reader, dir string) (*FontRegistry, error) {
+	registry := &FontRegistry{}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	first := true
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, `"`) {
+			return nil, fmt.Errorf("Line %v: expected a quoted font set name but got %q", lineNumber, line)
+		}
+		nameEnd := strings.Index(line[1:], `"`)
+		if nameEnd < 0 {
+			return nil, fmt.Errorf("Line %v: unterminated font set name", lineNumber)
+		}
+		name := line[1 : nameEnd+1]
+
+		fields := strings.Fields(line[nameEnd+2:])
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("Line %v: expected GUI, Art and Available columns for %q", lineNumber, name)
+		}
+
+		available := fields[2] != "0"
+		if !available && !first {
+			continue
+		}
+		first = false
+
+		sheet := fields[0]
+		size := fontSizePattern.FindStringSubmatch(sheet)
+		if size == nil {
+			return nil, fmt.Errorf("Line %v: can't tell the cell size of sheet %q", lineNumber, sheet)
+		}
+		width, _ := strconv.Atoi(size[1])
+		height, _ := strconv.Atoi(size[2])
+
+		registry.Fonts = append(registry.Fonts, FontInfo{
+			Name:   name,
+			Path:   filepath.Join(dir, sheet+".png"),
+			Width:  width,
+			Height: height,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Find looks up a font by name
+func (registry *FontRegistry) Find(name string) (int, bool) {
+	for i, font := range registry.Fonts {
+		if font.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// UseFonts gives the window a registry to pick fonts from with SetFont and CycleFont
+func (window *Window) UseFonts(registry *FontRegistry) {
+	window.fonts = registry
+	window.syncFontIndex()
+}
+
+func (window *Window) syncFontIndex() {
+	window.fontIndex = -1
+	if window.fonts == nil {
+		return
+	}
+	for i, font := range window.fonts.Fonts {
+		if font.Path == window.fontPath {
+			window.fontIndex = i
+			return
+		}
+	}
+}
+
+// FontName is the name of the registry font in use, empty if the font didn't come from the registry
+func (window *Window) FontName() string {
+	if window.fonts == nil || window.fontIndex < 0 {
+		return ""
+	}
+	return window.fonts.Fonts[window.fontIndex].Name
+}
+
+// SetFont switches to the named font from the window's registry
+func (window *Window) SetFont(name string) error {
+	if window.fonts == nil {
+		return errors.New("No font registry, call UseFonts first")
+	}
+	index, ok := window.fonts.Find(name)
+	if !ok {
+		return fmt.Errorf("Unknown font %q", name)
+	}
+	return window.setFontIndex(index)
+}
+
+// CycleFont switches to the next font in the window's registry, wrapping around at the end
+func (window *Window) CycleFont() error {
+	if window.fonts == nil || len(window.fonts.Fonts) == 0 {
+		return errors.New("No fonts to cycle through, call UseFonts first")
+	}
+	return window.setFontIndex((window.fontIndex + 1) % len(window.fonts.Fonts))
+}
+
+func (window *Window) setFontIndex(index int) error {
+	font := window.fonts.Fonts[index]
+	if err := window.ChangeFont(font.Path, font.Width, font.Height); err != nil {
+		return err
+	}
+	window.fontIndex = index
+	return nil
+}
@@ -0,0 +1,44 @@
+package gterm
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testFontConfig = `// Font Configuration
+// Set Name		GUI			Art			Available
+"CP437 12x12"		cp437_12x12		cp437_12x12		0	//	listed first so always available
+"CP437 16x16"		cp437_16x16		cp437_16x16		1
+"CP437 20x10"		cp437_20x10		cp437_20x10		0
+"CP437 8x8"		cp437_8x8		cp437_8x8		1	//	640x480
+`
+
+func TestParseFontConfig(t *testing.T) {
+	registry, err := ParseFontConfig(strings.NewReader(testFontConfig), "fonts")
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	expected := []FontInfo{
+		FontInfo{Name: "CP437 12x12", Path: filepath.Join("fonts", "cp437_12x12.png"), Width: 12, Height: 12},
+		FontInfo{Name: "CP437 16x16", Path: filepath.Join("fonts", "cp437_16x16.png"), Width: 16, Height: 16},
+		FontInfo{Name: "CP437 8x8", Path: filepath.Join("fonts", "cp437_8x8.png"), Width: 8, Height: 8},
+	}
+
+	if len(registry.Fonts) != len(expected) {
+		t.Fatalf("Expected %v fonts, got %+v", len(expected), registry.Fonts)
+	}
+	for i, font := range registry.Fonts {
+		if font != expected[i] {
+			t.Errorf("Expected %+v but got %+v", expected[i], font)
+		}
+	}
+}
+
+func TestParseFontConfigRejectsUnsizedSheet(t *testing.T) {
+	_, err := ParseFontConfig(strings.NewReader(`"Mystery" mystery mystery 1`), "fonts")
+	if err == nil {
+		t.Errorf("Expected an error for a sheet without a size")
+	}
+}
@@ -0,0 +1,146 @@
+package gterm
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// FrameCell is what one cell of a frame shows
+type FrameCell struct {
+	Glyph  rune
+	FColor sdl.Color
+	// BColor is NoColor where the window's background shows through
+	BColor sdl.Color
+}
+
+// Frame is a copy of the window's cells as they were drawn, safe to hand to
+// other goroutines. Decorations and floating glyphs are folded into the cells,
+// effects aren't included.
+type Frame struct {
+	Columns    int
+	Rows       int
+	Background sdl.Color
+	Cells      []FrameCell
+}
+
+// CellChange is a cell that differs from the previous frame
+type CellChange struct {
+	Col int
+	Row int
+	FrameCell
+}
+
+// FrameObserver is told about every frame a window draws. Frames are shared
+// between observers and must not be modified.
+type FrameObserver interface {
+	ObserveFrame(frame *Frame)
+}
+
+// Frame copies what every cell currently shows. A cell can't draw in pixel
+// space, so highlights tint its background, the other visible decorations
+// reverse its colours and floating glyphs land on the cell nearest them.
+func (window *Window) Frame() *Frame {
+	frame := &Frame{
+		Columns:    window.Columns,
+		Rows:       window.Rows,
+		Background: window.backgroundColor,
+		Cells:      make([]FrameCell, window.Columns*window.Rows),
+	}
+	for row := 0; row < window.Rows; row++ {
+		for col := 0; col < window.Columns; col++ {
+			frame.Cells[col+row*window.Columns] = window.frameCell(col, row)
+		}
+	}
+	for _, glyph := range window.floating {
+		col, row := int(math.Floor(glyph.X+0.5)), int(math.Floor(glyph.Y+0.5))
+		if col < 0 || col >= frame.Columns || row < 0 || row >= frame.Rows {
+			continue
+		}
+		cell := &frame.Cells[col+row*frame.Columns]
+		cell.Glyph, cell.FColor = glyph.Glyph, glyph.FColor
+		if glyph.BColor != NoColor {
+			cell.BColor = glyph.BColor
+		}
+	}
+	return frame
+}
+
+// frameCell is what the cell at col, row shows with its visible decorations applied
+func (window *Window) frameCell(col int, row int) FrameCell {
+	glyph, fColor, bColor, _ := window.CellAt(col, row)
+	cell := window.cells[col+row*window.Columns]
+	for i, decoration := range cell.decorations {
+		if i < 32 && cell.blinkHidden&(1<<uint(i)) != 0 {
+			continue
+		}
+
+		color := applyLight(decoration.Color, cell.light)
+		background := bColor
+		if background == NoColor {
+			background = window.backgroundColor
+		}
+		if decoration.Style == Highlight {
+			if background == NoColor {
+				background = sdl.Color{A: 255}
+			}
+			bColor = lerpColor(background, color, float64(color.A)/255)
+			continue
+		}
+
+		// Blank cells show the decoration's colour, like a block cursor
+		if glyph == ' ' || fColor == NoColor {
+			fColor = color
+		}
+		fColor, bColor = background, fColor
+	}
+	return FrameCell{Glyph: glyph, FColor: fColor, BColor: bColor}
+}
+
+// CellAt returns the cell at col, row, or a blank cell outside the frame
+func (frame *Frame) CellAt(col int, row int) FrameCell {
+	if col < 0 || col >= frame.Columns || row < 0 || row >= frame.Rows {
+		return FrameCell{Glyph: ' ', FColor: NoColor, BColor: NoColor}
+	}
+	return frame.Cells[col+row*frame.Columns]
+}
+
+// Changes lists the cells that differ from previous. Every cell is listed when
+// there is no previous frame or it was a different size.
+func (frame *Frame) Changes(previous *Frame) []CellChange {
+	full := previous == nil || previous.Columns != frame.Columns || previous.Rows != frame.Rows
+	var changes []CellChange
+	for i, cell := range frame.Cells {
+		if !full && previous.Cells[i] == cell {
+			continue
+		}
+		changes = append(changes, CellChange{Col: i % frame.Columns, Row: i / frame.Columns, FrameCell: cell})
+	}
+	return changes
+}
+
+// AddFrameObserver starts telling observer about each frame Refresh draws
+func (window *Window) AddFrameObserver(observer FrameObserver) {
+	window.observers = append(window.observers, observer)
+}
+
+// RemoveFrameObserver stops telling observer about frames
+func (window *Window) RemoveFrameObserver(observer FrameObserver) {
+	for i, o := range window.observers {
+		if o == observer {
+			window.observers = append(window.observers[:i], window.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+// publishFrame hands a copy of the frame just drawn to the observers
+func (window *Window) publishFrame() {
+	if len(window.observers) == 0 {
+		return
+	}
+	frame := window.Frame()
+	for _, observer := range window.observers {
+		observer.ObserveFrame(frame)
+	}
+}
@@ -0,0 +1,92 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func testFrame(columns int, rows int, glyphs string) *Frame {
+	frame := &Frame{Columns: columns, Rows: rows, Background: NoColor}
+	for _, glyph := range glyphs {
+		frame.Cells = append(frame.Cells, FrameCell{Glyph: glyph, FColor: NoColor, BColor: NoColor})
+	}
+	return frame
+}
+
+func TestFrameChanges(t *testing.T) {
+	previous := testFrame(3, 2, "abcdef")
+	frame := testFrame(3, 2, "abXdeY")
+	frame.Cells[3].FColor = sdl.Color{R: 255, A: 255}
+
+	changes := frame.Changes(previous)
+	want := []CellChange{
+		{Col: 2, Row: 0, FrameCell: frame.Cells[2]},
+		{Col: 0, Row: 1, FrameCell: frame.Cells[3]},
+		{Col: 2, Row: 1, FrameCell: frame.Cells[5]},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %v changes, got %v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("Change %v is %v, want %v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestFrameChangesFull(t *testing.T) {
+	frame := testFrame(2, 2, "abcd")
+	if changes := frame.Changes(nil); len(changes) != 4 {
+		t.Errorf("Expected every cell without a previous frame, got %v", changes)
+	}
+	if changes := frame.Changes(testFrame(4, 1, "abcd")); len(changes) != 4 {
+		t.Errorf("Expected every cell after a resize, got %v", changes)
+	}
+	if changes := frame.Changes(testFrame(2, 2, "abcd")); len(changes) != 0 {
+		t.Errorf("Expected no changes from the same frame, got %v", changes)
+	}
+}
+
+func TestFrameCellAtOutside(t *testing.T) {
+	frame := testFrame(1, 1, "a")
+	if cell := frame.CellAt(1, 0); cell.Glyph != ' ' || cell.BColor != NoColor {
+		t.Errorf("Expected a blank cell outside the frame, got %v", cell)
+	}
+}
+
+func TestFrameFoldsDecorationsAndFloating(t *testing.T) {
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	black := sdl.Color{A: 255}
+	window := NewVirtualWindow(4, 1)
+	window.SetBackgroundColor(black)
+	window.PutRune(0, 0, 'a', white, NoColor)
+	window.Decorate(0, 0, Decoration{Style: Highlight, Color: sdl.Color{R: 200, A: 128}})
+	window.PutRune(1, 0, 'b', white, NoColor)
+	window.Decorate(1, 0, Decoration{Style: Outline, Color: white})
+	window.Decorate(2, 0, Decoration{Style: Outline, Color: sdl.Color{G: 255, A: 255}})
+	window.AddFloatingGlyph('@', white, NoColor, 2.6, 0.2)
+
+	frame := window.Frame()
+	if cell := frame.CellAt(0, 0); cell.Glyph != 'a' || cell.BColor != (sdl.Color{R: 100, A: 255}) {
+		t.Errorf("Expected the highlight blended into the background, got %v", cell)
+	}
+	if cell := frame.CellAt(1, 0); cell.FColor != black || cell.BColor != white {
+		t.Errorf("Expected the outlined cell's colours reversed, got %v", cell)
+	}
+	if cell := frame.CellAt(2, 0); cell.BColor != (sdl.Color{G: 255, A: 255}) {
+		t.Errorf("Expected a blank outlined cell to show the outline colour, got %v", cell)
+	}
+	if cell := frame.CellAt(3, 0); cell.Glyph != '@' || cell.FColor != white {
+		t.Errorf("Expected the floating glyph on its nearest cell, got %v", cell)
+	}
+}
+
+func TestFrameHidesBlinkedDecorations(t *testing.T) {
+	window := NewVirtualWindow(1, 1)
+	window.Decorate(0, 0, Decoration{Style: Outline, Color: sdl.Color{R: 255, A: 255}, Blink: 100})
+	window.cells[0].blinkHidden = 1
+	if cell := window.Frame().CellAt(0, 0); cell.BColor != NoColor {
+		t.Errorf("Expected a hidden decoration to leave the cell alone, got %v", cell)
+	}
+}
@@ -0,0 +1,225 @@
+// Package gtermtest compares what a gterm Window draws against golden files
+// checked in under testdata. Run the tests with -update to rewrite the golden
+// files from the current output.
+package gtermtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thomas-holmes/gterm"
+)
+
+// Update rewrites golden files instead of comparing against them
+var Update = flag.Bool("update", false, "rewrite gterm golden files")
+
+// GoldenDir is where golden files are kept, relative to the package under test
+var GoldenDir = "testdata"
+
+// NewWindow constructs a window for text snapshots. It is never initialized,
+// so it works without a display or even SDL.
+func NewWindow(columns int, rows int) *gterm.Window {
+	return gterm.NewVirtualWindow(columns, rows)
+}
+
+// NewHeadlessWindow constructs a window drawing offscreen with SDL's software
+// renderer, for image snapshots. Destroy it when done.
+func NewHeadlessWindow(t testing.TB, columns int, rows int, fontPath string, fontW int, fontH int) *gterm.Window {
+	window := gterm.NewWindow(columns, rows, fontPath, fontW, fontH, false)
+	if err := window.InitHeadless(); err != nil {
+		t.Fatalf("Failed to initialize headless window: %v", err)
+	}
+	return window
+}
+
+func goldenPath(name string, ext string) string {
+	return filepath.Join(GoldenDir, name+ext)
+}
+
+func writeGolden(t testing.TB, path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create golden directory: %v", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write golden file: %v", err)
+	}
+}
+
+// readGolden returns the golden file at path. A missing one fails the test,
+// record it with -update.
+func readGolden(t testing.TB, path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("No golden file %s, run with -update to record it", path)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	return data
+}
+
+// AssertText compares the window's text, see Window.Text, against testdata/name.txt
+func AssertText(t testing.TB, window *gterm.Window, name string) {
+	actual := window.Text() + "\n"
+	path := goldenPath(name, ".txt")
+	if *Update {
+		writeGolden(t, path, []byte(actual))
+		return
+	}
+
+	expected := string(readGolden(t, path))
+	if diff := TextDiff(expected, actual); diff != "" {
+		t.Errorf("%s doesn't match the window:\n%s", path, diff)
+	}
+}
+
+// TextDiff describes how two grids of text differ, row by row, with the
+// changed cells marked underneath. It is empty when they are the same.
+func TextDiff(expected string, actual string) string {
+	expectedRows := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	actualRows := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	rows := len(expectedRows)
+	if len(actualRows) > rows {
+		rows = len(actualRows)
+	}
+
+	var diff bytes.Buffer
+	for row := 0; row < rows; row++ {
+		var want, got []rune
+		if row < len(expectedRows) {
+			want = []rune(expectedRows[row])
+		}
+		if row < len(actualRows) {
+			got = []rune(actualRows[row])
+		}
+		marks, changed := markChanges(want, got)
+		if !changed {
+			continue
+		}
+		fmt.Fprintf(&diff, "row %d\n", row)
+		fmt.Fprintf(&diff, "  - |%s|\n", string(want))
+		fmt.Fprintf(&diff, "  + |%s|\n", string(got))
+		fmt.Fprintf(&diff, "     %s\n", strings.TrimRight(string(marks), " "))
+	}
+	return diff.String()
+}
+
+// markChanges puts a ^ under every column that differs
+func markChanges(want []rune, got []rune) ([]rune, bool) {
+	columns := len(want)
+	if len(got) > columns {
+		columns = len(got)
+	}
+	marks := make([]rune, columns)
+	changed := false
+	for col := range marks {
+		marks[col] = ' '
+		if col >= len(want) || col >= len(got) || want[col] != got[col] {
+			marks[col] = '^'
+			changed = true
+		}
+	}
+	return marks, changed
+}
+
+// AssertPNG draws the window and compares it against testdata/name.png. On a
+// mismatch the actual frame and a diff with the changed pixels in red are
+// written next to the golden file, and the changed cells are listed.
+func AssertPNG(t testing.TB, window *gterm.Window, name string) {
+	actual, err := window.Snapshot()
+	if err != nil {
+		t.Fatalf("Failed to snapshot window: %v", err)
+	}
+
+	path := goldenPath(name, ".png")
+	if *Update {
+		writeGolden(t, path, encodePNG(t, actual))
+		return
+	}
+
+	expected, err := png.Decode(bytes.NewReader(readGolden(t, path)))
+	if err != nil {
+		t.Fatalf("Failed to decode golden file %s: %v", path, err)
+	}
+
+	diff, cells := ImageDiff(expected, actual, window.DisplayWPixel, window.DisplayHPixel)
+	if diff == nil {
+		return
+	}
+
+	writeGolden(t, goldenPath(name, ".actual.png"), encodePNG(t, actual))
+	writeGolden(t, goldenPath(name, ".diff.png"), encodePNG(t, diff))
+	t.Errorf("%s doesn't match the window, %d cells changed: %v\nsee %s and %s",
+		path, len(cells), summarizeCells(cells, 20), goldenPath(name, ".actual.png"), goldenPath(name, ".diff.png"))
+}
+
+func encodePNG(t testing.TB, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// ImageDiff compares two frames. When they differ it returns an image of the
+// actual frame faded out with changed pixels in red, and the cells, as
+// image.Points in columns and rows, the changes fall in. Frames of different
+// sizes differ everywhere.
+func ImageDiff(expected image.Image, actual image.Image, cellW int, cellH int) (*image.RGBA, []image.Point) {
+	bounds := actual.Bounds()
+	sameSize := expected.Bounds().Size() == bounds.Size()
+	offset := expected.Bounds().Min.Sub(bounds.Min)
+
+	diff := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	changedCells := make(map[image.Point]bool)
+	var cells []image.Point
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			got := color.RGBAModel.Convert(actual.At(x, y)).(color.RGBA)
+			want := color.RGBA{}
+			if sameSize {
+				want = color.RGBAModel.Convert(expected.At(x+offset.X, y+offset.Y)).(color.RGBA)
+			}
+			if sameSize && got == want {
+				gray := uint8((int(got.R) + int(got.G) + int(got.B)) / 3 / 4)
+				diff.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, color.RGBA{R: gray, G: gray, B: gray, A: 255})
+				continue
+			}
+			diff.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, color.RGBA{R: 255, A: 255})
+
+			cell := image.Point{X: (x - bounds.Min.X) / maxOne(cellW), Y: (y - bounds.Min.Y) / maxOne(cellH)}
+			if !changedCells[cell] {
+				changedCells[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	if len(cells) == 0 {
+		return nil, nil
+	}
+	return diff, cells
+}
+
+func maxOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+func summarizeCells(cells []image.Point, limit int) string {
+	if len(cells) <= limit {
+		return fmt.Sprint(cells)
+	}
+	return fmt.Sprintf("%v and %d more", cells[:limit], len(cells)-limit)
+}
@@ -0,0 +1,69 @@
+package gtermtest
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestTextDiffSame(t *testing.T) {
+	if diff := TextDiff("ab\ncd\n", "ab\ncd"); diff != "" {
+		t.Errorf("Expected no diff, got\n%s", diff)
+	}
+}
+
+func TestTextDiffMarksChangedCells(t *testing.T) {
+	diff := TextDiff("abc\ndef", "abc\nxeg")
+	expected := "row 1\n  - |def|\n  + |xeg|\n     ^ ^\n"
+	if diff != expected {
+		t.Errorf("Expected\n%q\ngot\n%q", expected, diff)
+	}
+}
+
+func TestTextDiffExtraRows(t *testing.T) {
+	diff := TextDiff("a", "a\nb")
+	if !strings.Contains(diff, "row 1") {
+		t.Errorf("Expected the added row in the diff, got\n%s", diff)
+	}
+}
+
+func TestImageDiffFindsChangedCells(t *testing.T) {
+	expected := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	actual := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	if diff, cells := ImageDiff(expected, actual, 4, 4); diff != nil || cells != nil {
+		t.Fatalf("Expected identical images not to differ, got cells %v", cells)
+	}
+
+	actual.SetRGBA(5, 1, color.RGBA{R: 10, A: 255})
+	diff, cells := ImageDiff(expected, actual, 4, 4)
+	if diff == nil || len(cells) != 1 || cells[0] != (image.Point{X: 1, Y: 0}) {
+		t.Fatalf("Expected cell (1, 0) to change, got %v", cells)
+	}
+	if diff.RGBAAt(5, 1) != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the changed pixel to be red, got %v", diff.RGBAAt(5, 1))
+	}
+}
+
+func TestImageDiffSizeMismatch(t *testing.T) {
+	expected := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	actual := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	if _, cells := ImageDiff(expected, actual, 4, 4); len(cells) != 2 {
+		t.Errorf("Expected every cell to differ, got %v", cells)
+	}
+}
+
+func TestAssertPNGHeadless(t *testing.T) {
+	window := NewHeadlessWindow(t, 12, 4, filepath.Join("..", "example", "atlas", "fonts", "cp437_8x8.png"), 8, 8)
+	defer window.Destroy()
+
+	window.SetBackgroundColor(sdl.Color{R: 20, G: 20, B: 40, A: 255})
+	window.PutString(1, 1, "gterm", sdl.Color{R: 255, G: 255, B: 255, A: 255})
+	window.PutRune(7, 1, '@', sdl.Color{R: 255, G: 200, B: 0, A: 255}, sdl.Color{R: 120, G: 0, B: 0, A: 255})
+	window.PutStringBg(1, 2, "golden", sdl.Color{R: 0, G: 255, B: 0, A: 255}, sdl.Color{R: 0, G: 0, B: 120, A: 255})
+
+	AssertPNG(t, window, "headless")
+}
@@ -0,0 +1,111 @@
+package gterm
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/img"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// InitHeadless initializes the window to draw into an offscreen surface with
+// SDL's software renderer instead of opening a desktop window. It is meant for
+// tests and tools, use Snapshot to see what was drawn.
+func (window *Window) InitHeadless() error {
+	if err := sdl.Init(0); err != nil {
+		return err
+	}
+
+	if flags := img.Init(img.INIT_PNG); flags&img.INIT_PNG == 0 {
+		return errors.New("Failed to initialize sdl2_img for PNG")
+	}
+
+	surface, err := sdl.CreateRGBSurface(0, int32(window.WidthPixel), int32(window.HeightPixel), 32, 0x000000ff, 0x0000ff00, 0x00ff0000, 0xff000000)
+	if err != nil {
+		return err
+	}
+	sdlRenderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		return err
+	}
+	if err := sdlRenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND); err != nil {
+		return err
+	}
+
+	window.surface = surface
+	window.SdlRenderer = sdlRenderer
+
+	window.fontSheet, err = window.loadFont(window.FontWPixel)
+	if err != nil {
+		return err
+	}
+
+	window.fps = newFpsCounter()
+
+	return nil
+}
+
+// Destroy releases everything Init or InitHeadless created
+func (window *Window) Destroy() {
+	window.resetTarget()
+	if window.fontSheet != nil {
+		window.fontSheet.Destroy()
+		window.fontSheet = nil
+	}
+	if window.SdlRenderer != nil {
+		window.SdlRenderer.Destroy()
+		window.SdlRenderer = nil
+	}
+	if window.surface != nil {
+		window.surface.Free()
+		window.surface = nil
+	}
+	if window.SdlWindow != nil {
+		window.SdlWindow.Destroy()
+		window.SdlWindow = nil
+	}
+}
+
+// Snapshot draws the whole frame and reads it back as an image
+func (window *Window) Snapshot() (*image.RGBA, error) {
+	window.updateSize()
+	window.tidyEffects()
+	window.advanceAnimations()
+	window.floatingMoving = window.advanceFloating()
+	window.needsRedraw()
+	window.redrawAll = true
+
+	window.compose()
+
+	w, h, err := window.SdlRenderer.GetOutputSize()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := image.NewRGBA(image.Rect(0, 0, w, h))
+	// RGBA32 is the packed format whose bytes are in R, G, B, A order, like image.RGBA, on any byte order
+	if err := window.SdlRenderer.ReadPixels(nil, uint32(sdl.PIXELFORMAT_RGBA32), unsafe.Pointer(&snapshot.Pix[0]), snapshot.Stride); err != nil {
+		return nil, err
+	}
+	window.SdlRenderer.Present()
+	return snapshot, nil
+}
+
+// Text returns the grid as text, one line per row, showing the glyph drawn on
+// top in each cell. Empty cells are spaces. It only needs the cells, so it
+// works on windows that were never initialized.
+func (window *Window) Text() string {
+	var text bytes.Buffer
+	for row := 0; row < window.Rows; row++ {
+		if row > 0 {
+			text.WriteByte('\n')
+		}
+		for col := 0; col < window.Columns; col++ {
+			glyph, _, _, _ := window.CellAt(col, row)
+			text.WriteRune(glyph)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,215 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// HitEventKind says what the mouse did to a hit region
+type HitEventKind int
+
+const (
+	// HoverEnter is sent when the mouse moves onto a region
+	HoverEnter HitEventKind = iota
+	// HoverLeave is sent when the mouse moves off a region, or the region goes away
+	HoverLeave
+	// Click is sent when a button is pressed and released over the same region
+	Click
+)
+
+func (kind HitEventKind) String() string {
+	switch kind {
+	case HoverEnter:
+		return "HoverEnter"
+	case HoverLeave:
+		return "HoverLeave"
+	case Click:
+		return "Click"
+	}
+	return "Unknown"
+}
+
+// HitEvent reports the mouse on a hit region by the region's ID. Run delivers
+// them to HandleEvent right after the mouse event that caused them.
+type HitEvent struct {
+	Kind HitEventKind
+	ID   string
+	// Col and Row are the cell the mouse is on, counted from the region's top
+	// left. They are zero for HoverLeave.
+	Col int
+	Row int
+	// Button is the mouse button clicked, zero for hovering
+	Button uint8
+}
+
+// hitRegion is a rectangle of cells the mouse is tracked over
+type hitRegion struct {
+	id     string
+	col    int
+	row    int
+	width  int
+	height int
+}
+
+func (region hitRegion) contains(col int, row int) bool {
+	return col >= region.col && col < region.col+region.width && row >= region.row && row < region.row+region.height
+}
+
+// hitRegions turns mouse positions in cells into events on the regions under them
+type hitRegions struct {
+	next    []hitRegion
+	live    []hitRegion
+	hovered string
+
+	mouseIn  bool
+	mouseCol int
+	mouseRow int
+
+	pressed map[uint8]string
+	queued  []sdl.Event
+}
+
+// add registers a region for the next frame, replacing one with the same id
+func (hits *hitRegions) add(region hitRegion) {
+	for i := range hits.next {
+		if hits.next[i].id == region.id {
+			hits.next[i] = region
+			return
+		}
+	}
+	hits.next = append(hits.next, region)
+}
+
+// swap makes the regions added since the last swap the live ones
+func (hits *hitRegions) swap() {
+	hits.live, hits.next = hits.next, hits.live[:0]
+	hits.hover()
+}
+
+// at finds the region over a cell, the last added wins where they overlap
+func (hits *hitRegions) at(col int, row int) (hitRegion, bool) {
+	for i := len(hits.live) - 1; i >= 0; i-- {
+		if hits.live[i].contains(col, row) {
+			return hits.live[i], true
+		}
+	}
+	return hitRegion{}, false
+}
+
+// move tracks the mouse to a cell, inside is false once it leaves the grid
+func (hits *hitRegions) move(col int, row int, inside bool) {
+	hits.mouseCol, hits.mouseRow, hits.mouseIn = col, row, inside
+	hits.hover()
+}
+
+// hover queues enter and leave events when the region under the mouse changes
+func (hits *hitRegions) hover() {
+	region, ok := hits.at(hits.mouseCol, hits.mouseRow)
+	if !hits.mouseIn {
+		ok = false
+	}
+	if ok && region.id == hits.hovered {
+		return
+	}
+
+	if hits.hovered != "" {
+		hits.queue(HitEvent{Kind: HoverLeave, ID: hits.hovered})
+		hits.hovered = ""
+	}
+	if ok {
+		hits.hovered = region.id
+		hits.queue(HitEvent{Kind: HoverEnter, ID: region.id, Col: hits.mouseCol - region.col, Row: hits.mouseRow - region.row})
+	}
+}
+
+func (hits *hitRegions) press(button uint8) {
+	if hits.pressed == nil {
+		hits.pressed = make(map[uint8]string)
+	}
+	hits.pressed[button] = hits.hovered
+}
+
+// release queues a click when the button went down over the same region
+func (hits *hitRegions) release(button uint8) {
+	pressed, ok := hits.pressed[button]
+	delete(hits.pressed, button)
+	if !ok || pressed == "" || pressed != hits.hovered {
+		return
+	}
+	region, _ := hits.at(hits.mouseCol, hits.mouseRow)
+	hits.queue(HitEvent{Kind: Click, ID: region.id, Col: hits.mouseCol - region.col, Row: hits.mouseRow - region.row, Button: button})
+}
+
+func (hits *hitRegions) queue(event HitEvent) {
+	hits.queued = append(hits.queued, &event)
+}
+
+// flush returns the queued events and empties the queue
+func (hits *hitRegions) flush() []sdl.Event {
+	queued := hits.queued
+	hits.queued = nil
+	return queued
+}
+
+// AddHitRegion registers a width x height rectangle of cells that reports the
+// mouse as HitEvents carrying id. Register regions while drawing, each
+// Refresh replaces the regions in use with those added since the last one.
+// Adding an id again in the same frame moves its region. Empty ids are ignored.
+func (window *Window) AddHitRegion(id string, col int, row int, width int, height int) {
+	if id == "" || width <= 0 || height <= 0 {
+		return
+	}
+	window.hits.add(hitRegion{id: id, col: col, row: row, width: width, height: height})
+}
+
+// HitRegionAt returns the id of the region over a cell in the frame showing
+func (window *Window) HitRegionAt(col int, row int) (string, bool) {
+	region, ok := window.hits.at(col, row)
+	return region.id, ok
+}
+
+// Hovered returns the id of the region under the mouse, empty when there isn't one
+func (window *Window) Hovered() string {
+	return window.hits.hovered
+}
+
+// CellAtPoint converts window coordinates, as mouse events report them, to
+// the cell under them. ok is false outside the grid.
+func (window *Window) CellAtPoint(x int32, y int32) (col int, row int, ok bool) {
+	px, py := int(x), int(y)
+	if window.SdlWindow != nil && window.SdlRenderer != nil {
+		// On high-DPI displays the drawable has more pixels than the window has points
+		w, h := window.SdlWindow.GetSize()
+		outputW, outputH, err := window.SdlRenderer.GetOutputSize()
+		if err == nil && w > 0 && h > 0 {
+			px, py = px*outputW/w, py*outputH/h
+		}
+	}
+	if px < 0 || py < 0 || window.DisplayWPixel <= 0 || window.DisplayHPixel <= 0 {
+		return 0, 0, false
+	}
+
+	col, row = px/window.DisplayWPixel, py/window.DisplayHPixel
+	if col >= window.Columns || row >= window.Rows {
+		return 0, 0, false
+	}
+	return col, row, true
+}
+
+// trackMouse follows mouse events over the hit regions
+func (window *Window) trackMouse(event sdl.Event) {
+	switch e := event.(type) {
+	case *sdl.MouseMotionEvent:
+		window.hits.move(window.CellAtPoint(e.X, e.Y))
+	case *sdl.MouseButtonEvent:
+		window.hits.move(window.CellAtPoint(e.X, e.Y))
+		if e.Type == sdl.MOUSEBUTTONDOWN {
+			window.hits.press(e.Button)
+		} else {
+			window.hits.release(e.Button)
+		}
+	case *sdl.WindowEvent:
+		if e.Event == sdl.WINDOWEVENT_LEAVE {
+			window.hits.move(0, 0, false)
+		}
+	}
+}
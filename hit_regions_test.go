@@ -0,0 +1,117 @@
+package gterm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// hitLog is an app that records the hit events it sees
+type hitLog struct {
+	events []string
+}
+
+func (app *hitLog) HandleEvent(event sdl.Event) {
+	if hit, ok := event.(*HitEvent); ok {
+		app.events = append(app.events, fmt.Sprintf("%v %v %v,%v", hit.Kind, hit.ID, hit.Col, hit.Row))
+	}
+}
+func (app *hitLog) Update(delta uint32)   {}
+func (app *hitLog) Render(window *Window) {}
+func (app *hitLog) Animating() bool       { return false }
+func (app *hitLog) Done() bool            { return false }
+
+func (app *hitLog) expect(t *testing.T, expected ...string) {
+	t.Helper()
+	if fmt.Sprint(app.events) != fmt.Sprint(expected) {
+		t.Fatalf("Expected %v, got %v", expected, app.events)
+	}
+	app.events = nil
+}
+
+func TestHitRegionHoverAndClick(t *testing.T) {
+	window := NewWindow(20, 10, "", 8, 16, false)
+	window.AddHitRegion("inventory", 2, 2, 10, 5)
+	window.AddHitRegion("item", 2, 3, 10, 1)
+	window.hits.swap()
+
+	app := &hitLog{}
+	window.deliver(app, &sdl.MouseMotionEvent{X: 4 * 8, Y: 2 * 16})
+	app.expect(t, "HoverEnter inventory 2,0")
+
+	window.deliver(app, &sdl.MouseMotionEvent{X: 5 * 8, Y: 3*16 + 5})
+	app.expect(t, "HoverLeave inventory 0,0", "HoverEnter item 3,0")
+
+	window.deliver(app, &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONDOWN, Button: 1, X: 5 * 8, Y: 3 * 16})
+	window.deliver(app, &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONUP, Button: 1, X: 6 * 8, Y: 3 * 16})
+	app.expect(t, "Click item 4,0")
+
+	if window.Hovered() != "item" {
+		t.Errorf("Expected item hovered, got %q", window.Hovered())
+	}
+
+	window.deliver(app, &sdl.MouseMotionEvent{X: 19 * 8, Y: 9 * 16})
+	app.expect(t, "HoverLeave item 0,0")
+}
+
+func TestHitRegionClickNeedsPressAndReleaseOnIt(t *testing.T) {
+	window := NewWindow(20, 10, "", 8, 16, false)
+	window.AddHitRegion("a", 0, 0, 5, 1)
+	window.AddHitRegion("b", 5, 0, 5, 1)
+	window.hits.swap()
+
+	app := &hitLog{}
+	window.deliver(app, &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONDOWN, Button: 1, X: 8, Y: 0})
+	window.deliver(app, &sdl.MouseButtonEvent{Type: sdl.MOUSEBUTTONUP, Button: 1, X: 6 * 8, Y: 0})
+	app.expect(t, "HoverEnter a 1,0", "HoverLeave a 0,0", "HoverEnter b 1,0")
+}
+
+func TestHitRegionsFollowFrames(t *testing.T) {
+	window := NewWindow(20, 10, "", 8, 16, false)
+	window.AddHitRegion("menu", 0, 0, 5, 5)
+	window.hits.swap()
+
+	app := &hitLog{}
+	window.deliver(app, &sdl.MouseMotionEvent{X: 8, Y: 16})
+	app.expect(t, "HoverEnter menu 1,1")
+
+	// Drawn again in the next frame the region keeps its hover
+	window.AddHitRegion("menu", 0, 0, 5, 5)
+	window.AddHitRegion("menu", 0, 0, 6, 6)
+	window.hits.swap()
+	window.deliverHits(app)
+	app.expect(t)
+	if id, ok := window.HitRegionAt(5, 5); !ok || id != "menu" {
+		t.Errorf("Expected the second menu region to replace the first, got %q %v", id, ok)
+	}
+
+	// Not drawn and it goes away
+	window.hits.swap()
+	window.deliverHits(app)
+	app.expect(t, "HoverLeave menu 0,0")
+	if _, ok := window.HitRegionAt(1, 1); ok {
+		t.Error("Expected no regions left")
+	}
+}
+
+func TestCellAtPoint(t *testing.T) {
+	window := NewWindow(20, 10, "", 8, 16, false)
+	tests := []struct {
+		x, y     int32
+		col, row int
+		ok       bool
+	}{
+		{0, 0, 0, 0, true},
+		{15, 31, 1, 1, true},
+		{159, 159, 19, 9, true},
+		{160, 0, 0, 0, false},
+		{-1, 0, 0, 0, false},
+	}
+	for _, test := range tests {
+		col, row, ok := window.CellAtPoint(test.x, test.y)
+		if col != test.col || row != test.row || ok != test.ok {
+			t.Errorf("(%v,%v): expected %v,%v %v, got %v,%v %v", test.x, test.y, test.col, test.row, test.ok, col, row, ok)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package gterm
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"unicode"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// ExportHTML writes the window's cells as a self-contained HTML page, a <pre>
+// of coloured spans that can be pasted anywhere an image can't
+func (window *Window) ExportHTML(w io.Writer) error {
+	title := window.title
+	if title == "" {
+		title = "gterm"
+	}
+	_, err := w.Write(frameHTML(window.Frame(), title))
+	return err
+}
+
+// htmlColor is a colour as CSS, NoColor falls back to fallback
+func htmlColor(color sdl.Color, fallback string) string {
+	if color == NoColor {
+		return fallback
+	}
+	return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
+}
+
+// frameHTML renders a frame as a page. Each row is split into runs of cells
+// with the same colours, one span per run.
+func frameHTML(frame *Frame, title string) []byte {
+	background := htmlColor(frame.Background, "#000000")
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%v</title>\n", html.EscapeString(title))
+	fmt.Fprintf(&out, "<style>\nbody { background: %v; margin: 0; }\n", background)
+	fmt.Fprintf(&out, "pre { font-family: monospace; line-height: 1.2; color: #ffffff; background: %v; margin: 0; padding: 1em; }\n</style>\n", background)
+	out.WriteString("</head>\n<body>\n<pre>")
+
+	for row := 0; row < frame.Rows; row++ {
+		if row > 0 {
+			out.WriteByte('\n')
+		}
+		for col := 0; col < frame.Columns; {
+			start := frame.CellAt(col, row)
+			var run bytes.Buffer
+			for ; col < frame.Columns; col++ {
+				cell := frame.CellAt(col, row)
+				if cell.FColor != start.FColor || cell.BColor != start.BColor {
+					break
+				}
+				glyph := cell.Glyph
+				if !unicode.IsPrint(glyph) {
+					glyph = ' '
+				}
+				run.WriteString(html.EscapeString(string(glyph)))
+			}
+
+			if start.FColor == NoColor && start.BColor == NoColor {
+				out.Write(run.Bytes())
+				continue
+			}
+			out.WriteString("<span style=\"")
+			if start.FColor != NoColor {
+				fmt.Fprintf(&out, "color: %v;", htmlColor(start.FColor, ""))
+			}
+			if start.BColor != NoColor {
+				fmt.Fprintf(&out, "background: %v;", htmlColor(start.BColor, ""))
+			}
+			out.WriteString("\">")
+			out.Write(run.Bytes())
+			out.WriteString("</span>")
+		}
+	}
+
+	out.WriteString("</pre>\n</body>\n</html>\n")
+	return out.Bytes()
+}
@@ -0,0 +1,41 @@
+package gterm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestFrameHTML(t *testing.T) {
+	red := sdl.Color{R: 255, A: 255}
+	blue := sdl.Color{B: 255, A: 255}
+	frame := &Frame{Columns: 4, Rows: 2, Background: NoColor, Cells: []FrameCell{
+		{Glyph: 'a', FColor: red, BColor: NoColor},
+		{Glyph: 'b', FColor: red, BColor: NoColor},
+		{Glyph: '<', FColor: red, BColor: blue},
+		{Glyph: 0, FColor: NoColor, BColor: NoColor},
+		{Glyph: '@', FColor: NoColor, BColor: NoColor},
+		{Glyph: '&', FColor: NoColor, BColor: NoColor},
+		{Glyph: 'x', FColor: blue, BColor: NoColor},
+		{Glyph: 'y', FColor: blue, BColor: NoColor},
+	}}
+
+	page := string(frameHTML(frame, "Morgue"))
+
+	want := "<pre>" +
+		`<span style="color: #ff0000;">ab</span>` +
+		`<span style="color: #ff0000;background: #0000ff;">&lt;</span>` +
+		" \n@&amp;" +
+		`<span style="color: #0000ff;">xy</span>` +
+		"</pre>"
+	if !strings.Contains(page, want) {
+		t.Errorf("Expected merged runs\n%v\nin\n%v", want, page)
+	}
+	if !strings.Contains(page, "<title>Morgue</title>") {
+		t.Errorf("Expected the title in\n%v", page)
+	}
+	if !strings.Contains(page, "background: #000000") {
+		t.Errorf("Expected a black page without a window background in\n%v", page)
+	}
+}
@@ -0,0 +1,245 @@
+package gterm
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// ImageCell is a single converted cell of an image
+type ImageCell struct {
+	Glyph  rune
+	FColor sdl.Color
+	BColor sdl.Color
+}
+
+// CellImage is an image converted to a block of cells
+type CellImage struct {
+	Columns int
+	Rows    int
+	Cells   []ImageCell
+}
+
+// At returns the cell at (col, row)
+func (cells *CellImage) At(col int, row int) ImageCell {
+	return cells.Cells[col+row*cells.Columns]
+}
+
+// imageSamples is how many samples are taken across and down each cell
+const imageSamples = 4
+
+type imageCandidate struct {
+	glyph rune
+	mask  [imageSamples * imageSamples]bool
+}
+
+// imageCandidates are the glyphs an image cell may become, each with the
+// samples its foreground covers. Earlier candidates win ties, so the simplest
+// glyph is picked when several fit equally well.
+var imageCandidates = buildImageCandidates()
+
+func buildImageCandidates() []imageCandidate {
+	quadrant := func(glyph rune, bits uint) imageCandidate {
+		candidate := imageCandidate{glyph: glyph}
+		for y := 0; y < imageSamples; y++ {
+			for x := 0; x < imageSamples; x++ {
+				quad := uint((y/2)*2 + x/2)
+				candidate.mask[y*imageSamples+x] = bits&(1<<quad) != 0
+			}
+		}
+		return candidate
+	}
+	shade := func(glyph rune, covered func(x, y int) bool) imageCandidate {
+		candidate := imageCandidate{glyph: glyph}
+		for y := 0; y < imageSamples; y++ {
+			for x := 0; x < imageSamples; x++ {
+				candidate.mask[y*imageSamples+x] = covered(x, y)
+			}
+		}
+		return candidate
+	}
+
+	candidates := []imageCandidate{
+		quadrant('█', 15),
+		quadrant('▀', 3),
+		quadrant('▌', 5),
+	}
+	for bits, glyph := range quadrantGlyphs {
+		if bits == 0 || bits == 3 || bits == 5 || bits == 10 || bits == 12 || bits == 15 {
+			// Blank is the same as full and the remaining halves are the same as their opposites
+			continue
+		}
+		candidates = append(candidates, quadrant(glyph, uint(bits)))
+	}
+	candidates = append(candidates,
+		shade('░', func(x, y int) bool { return x%2 == 0 && y%2 == 0 }),
+		shade('▒', func(x, y int) bool { return (x+y)%2 == 0 }),
+		shade('▓', func(x, y int) bool { return !(x%2 == 0 && y%2 == 0) }),
+	)
+	return candidates
+}
+
+type sampleColor struct {
+	r, g, b int
+}
+
+func (c sampleColor) distance(o sampleColor) int {
+	dr, dg, db := c.r-o.r, c.g-o.g, c.b-o.b
+	return dr*dr + dg*dg + db*db
+}
+
+func (c sampleColor) sdlColor() sdl.Color {
+	return sdl.Color{R: uint8(c.r), G: uint8(c.g), B: uint8(c.b), A: 255}
+}
+
+// averageRegion averages [x0, x1) x [y0, y1) of img, composited over black
+func averageRegion(img image.Image, x0, y0, x1, y1 int) sampleColor {
+	var r, g, b, n int
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			r += int(c.R) * int(c.A) / 255
+			g += int(c.G) * int(c.A) / 255
+			b += int(c.B) * int(c.A) / 255
+			n++
+		}
+	}
+	if n == 0 {
+		return sampleColor{}
+	}
+	return sampleColor{r: r / n, g: g / n, b: b / n}
+}
+
+func sampleCell(img image.Image, col int, row int, columns int, rows int) [imageSamples * imageSamples]sampleColor {
+	var samples [imageSamples * imageSamples]sampleColor
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	across, down := columns*imageSamples, rows*imageSamples
+
+	for sy := 0; sy < imageSamples; sy++ {
+		y0 := bounds.Min.Y + (row*imageSamples+sy)*h/down
+		y1 := bounds.Min.Y + (row*imageSamples+sy+1)*h/down
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for sx := 0; sx < imageSamples; sx++ {
+			x0 := bounds.Min.X + (col*imageSamples+sx)*w/across
+			x1 := bounds.Min.X + (col*imageSamples+sx+1)*w/across
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			samples[sy*imageSamples+sx] = averageRegion(img, x0, y0, x1, y1)
+		}
+	}
+	return samples
+}
+
+func meanOf(samples []sampleColor) sampleColor {
+	var mean sampleColor
+	if len(samples) == 0 {
+		return mean
+	}
+	for _, s := range samples {
+		mean.r += s.r
+		mean.g += s.g
+		mean.b += s.b
+	}
+	n := len(samples)
+	return sampleColor{r: (mean.r + n/2) / n, g: (mean.g + n/2) / n, b: (mean.b + n/2) / n}
+}
+
+// fitCell picks the candidate glyph and colours closest to the samples
+func fitCell(samples [imageSamples * imageSamples]sampleColor) ImageCell {
+	var best ImageCell
+	bestError := -1
+
+	fg := make([]sampleColor, 0, len(samples))
+	bg := make([]sampleColor, 0, len(samples))
+	for _, candidate := range imageCandidates {
+		fg, bg = fg[:0], bg[:0]
+		for i, covered := range candidate.mask {
+			if covered {
+				fg = append(fg, samples[i])
+			} else {
+				bg = append(bg, samples[i])
+			}
+		}
+		fMean, bMean := meanOf(fg), meanOf(bg)
+		if len(bg) == 0 {
+			bMean = fMean
+		}
+
+		errorSum := 0
+		for i, covered := range candidate.mask {
+			if covered {
+				errorSum += samples[i].distance(fMean)
+			} else {
+				errorSum += samples[i].distance(bMean)
+			}
+		}
+
+		if bestError < 0 || errorSum < bestError {
+			bestError = errorSum
+			best = ImageCell{Glyph: candidate.glyph, FColor: fMean.sdlColor(), BColor: bMean.sdlColor()}
+		}
+	}
+	return best
+}
+
+// ImageToCells converts img to columns x rows cells. Each cell becomes a full,
+// half or quarter block or a shade glyph, whichever with its best foreground
+// and background colours matches the image most closely. The same image always
+// converts to the same cells.
+func ImageToCells(img image.Image, columns int, rows int) *CellImage {
+	cells := &CellImage{
+		Columns: columns,
+		Rows:    rows,
+		Cells:   make([]ImageCell, columns*rows),
+	}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			cells.Cells[col+row*columns] = fitCell(sampleCell(img, col, row, columns, rows))
+		}
+	}
+	return cells
+}
+
+// LoadPNGCells reads the PNG at path and converts it to columns x rows cells
+func LoadPNGCells(path string, columns int, rows int) (*CellImage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return ImageToCells(img, columns, rows), nil
+}
+
+// PutCellImage puts converted cells into the window with their top left corner at (col, row)
+func (window *Window) PutCellImage(col int, row int, cells *CellImage) error {
+	for y := 0; y < cells.Rows; y++ {
+		for x := 0; x < cells.Columns; x++ {
+			cell := cells.At(x, y)
+			if err := window.PutRune(col+x, row+y, cell.Glyph, cell.FColor, cell.BColor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PutPNG converts the PNG at path to fill the width x height rect at (col, row)
+func (window *Window) PutPNG(path string, col int, row int, width int, height int) error {
+	cells, err := LoadPNGCells(path, width, height)
+	if err != nil {
+		return err
+	}
+	return window.PutCellImage(col, row, cells)
+}
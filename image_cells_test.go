@@ -0,0 +1,76 @@
+package gterm
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// testImage builds a 4 pixel wide image per cell from rows of pixel codes
+func testImage(palette map[byte]color.Color, rows ...string) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x := 0; x < len(row); x++ {
+			img.Set(x, y, palette[row[x]])
+		}
+	}
+	return img
+}
+
+var testPalette = map[byte]color.Color{
+	'r': color.NRGBA{R: 255, A: 255},
+	'b': color.NRGBA{B: 255, A: 255},
+	'g': color.NRGBA{G: 255, A: 255},
+	'w': color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+	'k': color.NRGBA{A: 255},
+}
+
+func TestImageToCells(t *testing.T) {
+	img := testImage(testPalette,
+		"rrrrggggwkwkrrbb",
+		"rrrrggggkwkwrrbb",
+		"bbbbggggwkwkbbbb",
+		"bbbbggggkwkwbbbb",
+	)
+
+	cells := ImageToCells(img, 4, 1)
+
+	red := sdl.Color{R: 255, A: 255}
+	blue := sdl.Color{B: 255, A: 255}
+	green := sdl.Color{G: 255, A: 255}
+	white := sdl.Color{R: 255, G: 255, B: 255, A: 255}
+	black := sdl.Color{A: 255}
+
+	expected := []ImageCell{
+		ImageCell{Glyph: '▀', FColor: red, BColor: blue},
+		ImageCell{Glyph: '█', FColor: green, BColor: green},
+		ImageCell{Glyph: '▒', FColor: white, BColor: black},
+		ImageCell{Glyph: '▘', FColor: red, BColor: blue},
+	}
+	for col, e := range expected {
+		if cell := cells.At(col, 0); cell != e {
+			t.Errorf("Cell %v: expected %q %+v on %+v but got %q %+v on %+v", col, e.Glyph, e.FColor, e.BColor, cell.Glyph, cell.FColor, cell.BColor)
+		}
+	}
+}
+
+func TestImageToCellsIsDeterministic(t *testing.T) {
+	img := testImage(testPalette,
+		"rgbwkrgb",
+		"gbwkrgbw",
+		"bwkrgbwk",
+		"wkrgbwkr",
+	)
+
+	first := ImageToCells(img, 2, 1)
+	for i := 0; i < 10; i++ {
+		again := ImageToCells(img, 2, 1)
+		for j := range first.Cells {
+			if first.Cells[j] != again.Cells[j] {
+				t.Fatalf("Conversion %v differs at cell %v: %+v vs %+v", i, j, first.Cells[j], again.Cells[j])
+			}
+		}
+	}
+}
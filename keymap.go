@@ -0,0 +1,331 @@
+package gterm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Action names something the player can do, like "MoveNorthWest" or "OpenInventory"
+type Action string
+
+// Movement actions bound by the built in presets
+const (
+	MoveNorth     Action = "MoveNorth"
+	MoveSouth     Action = "MoveSouth"
+	MoveEast      Action = "MoveEast"
+	MoveWest      Action = "MoveWest"
+	MoveNorthEast Action = "MoveNorthEast"
+	MoveNorthWest Action = "MoveNorthWest"
+	MoveSouthEast Action = "MoveSouthEast"
+	MoveSouthWest Action = "MoveSouthWest"
+	Wait          Action = "Wait"
+)
+
+// Direction returns the (dx, dy) step of a movement action, ok is false for anything else
+func (action Action) Direction() (dx int, dy int, ok bool) {
+	switch action {
+	case MoveNorth:
+		return 0, -1, true
+	case MoveSouth:
+		return 0, 1, true
+	case MoveEast:
+		return 1, 0, true
+	case MoveWest:
+		return -1, 0, true
+	case MoveNorthEast:
+		return 1, -1, true
+	case MoveNorthWest:
+		return -1, -1, true
+	case MoveSouthEast:
+		return 1, 1, true
+	case MoveSouthWest:
+		return -1, 1, true
+	}
+	return 0, 0, false
+}
+
+// Modifier is a set of modifier keys, without caring whether the left or right one is held
+type Modifier uint8
+
+const (
+	ModShift Modifier = 1 << iota
+	ModCtrl
+	ModAlt
+	ModGUI
+)
+
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{ModCtrl, "Ctrl"},
+	{ModAlt, "Alt"},
+	{ModGUI, "GUI"},
+	{ModShift, "Shift"},
+}
+
+func modifiersFrom(mod sdl.Keymod) Modifier {
+	var modifiers Modifier
+	if mod&sdl.KMOD_SHIFT != 0 {
+		modifiers |= ModShift
+	}
+	if mod&sdl.KMOD_CTRL != 0 {
+		modifiers |= ModCtrl
+	}
+	if mod&sdl.KMOD_ALT != 0 {
+		modifiers |= ModAlt
+	}
+	if mod&sdl.KMOD_GUI != 0 {
+		modifiers |= ModGUI
+	}
+	return modifiers
+}
+
+// KeyChord is a key along with the modifiers that must be held with it
+type KeyChord struct {
+	Key sdl.Keycode
+	Mod Modifier
+}
+
+func (chord KeyChord) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if chord.Mod&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	parts = append(parts, sdl.GetKeyName(chord.Key))
+	return strings.Join(parts, "+")
+}
+
+// ParseChord reads a chord such as "Shift+.", "Ctrl+Alt+x" or "Keypad 7". Key
+// names are the ones SDL uses.
+func ParseChord(text string) (KeyChord, error) {
+	var chord KeyChord
+
+	text = strings.TrimSpace(text)
+	parts := strings.Split(text, "+")
+	keyName := parts[len(parts)-1]
+	parts = parts[:len(parts)-1]
+	if keyName == "" && len(parts) > 0 && parts[len(parts)-1] == "" {
+		// A trailing "++" binds the plus key itself
+		keyName = "+"
+		parts = parts[:len(parts)-1]
+	}
+
+	for _, part := range parts {
+		found := false
+		for _, m := range modifierNames {
+			if strings.EqualFold(strings.TrimSpace(part), m.name) {
+				chord.Mod |= m.mod
+				found = true
+			}
+		}
+		if !found {
+			return chord, fmt.Errorf("Unknown modifier %q in key chord %q", part, text)
+		}
+	}
+
+	chord.Key = sdl.GetKeyFromName(strings.TrimSpace(keyName))
+	if chord.Key == sdl.K_UNKNOWN {
+		return chord, fmt.Errorf("Unknown key %q in key chord %q", keyName, text)
+	}
+	return chord, nil
+}
+
+// Binding pairs a chord with the action it triggers
+type Binding struct {
+	Chord  KeyChord
+	Action Action
+}
+
+// Keymap resolves key presses to actions
+type Keymap struct {
+	bindings map[KeyChord]Action
+	order    []KeyChord
+}
+
+// NewKeymap constructs an empty keymap
+func NewKeymap() *Keymap {
+	return &Keymap{bindings: make(map[KeyChord]Action)}
+}
+
+// Bind makes chord trigger action, replacing whatever it was bound to before
+func (keymap *Keymap) Bind(chord KeyChord, action Action) {
+	if _, ok := keymap.bindings[chord]; !ok {
+		keymap.order = append(keymap.order, chord)
+	}
+	keymap.bindings[chord] = action
+}
+
+// BindKey binds an unmodified key to action
+func (keymap *Keymap) BindKey(key sdl.Keycode, action Action) {
+	keymap.Bind(KeyChord{Key: key}, action)
+}
+
+// Unbind removes whatever chord was bound to
+func (keymap *Keymap) Unbind(chord KeyChord) {
+	if _, ok := keymap.bindings[chord]; !ok {
+		return
+	}
+	delete(keymap.bindings, chord)
+	insertionIndex := 0
+	for _, c := range keymap.order {
+		if c != chord {
+			keymap.order[insertionIndex] = c
+			insertionIndex++
+		}
+	}
+	keymap.order = keymap.order[:insertionIndex]
+}
+
+// Resolve finds the action bound to key pressed with mod held
+func (keymap *Keymap) Resolve(key sdl.Keycode, mod sdl.Keymod) (Action, bool) {
+	action, ok := keymap.bindings[KeyChord{Key: key, Mod: modifiersFrom(mod)}]
+	return action, ok
+}
+
+// Lookup finds the action bound to a key down event, anything else never matches
+func (keymap *Keymap) Lookup(event sdl.Event) (Action, bool) {
+	if e, ok := event.(*sdl.KeyDownEvent); ok {
+		return keymap.Resolve(e.Keysym.Sym, sdl.Keymod(e.Keysym.Mod))
+	}
+	return "", false
+}
+
+// Bindings lists every binding grouped by action, for help screens
+func (keymap *Keymap) Bindings() []Binding {
+	bindings := make([]Binding, 0, len(keymap.order))
+	for _, chord := range keymap.order {
+		bindings = append(bindings, Binding{Chord: chord, Action: keymap.bindings[chord]})
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return bindings[i].Action < bindings[j].Action
+	})
+	return bindings
+}
+
+// ChordsFor lists the chords bound to action in the order they were bound
+func (keymap *Keymap) ChordsFor(action Action) []KeyChord {
+	var chords []KeyChord
+	for _, chord := range keymap.order {
+		if keymap.bindings[chord] == action {
+			chords = append(chords, chord)
+		}
+	}
+	return chords
+}
+
+var presets = map[string][]Binding{
+	"vi": {
+		{KeyChord{Key: sdl.K_h}, MoveWest},
+		{KeyChord{Key: sdl.K_j}, MoveSouth},
+		{KeyChord{Key: sdl.K_k}, MoveNorth},
+		{KeyChord{Key: sdl.K_l}, MoveEast},
+		{KeyChord{Key: sdl.K_y}, MoveNorthWest},
+		{KeyChord{Key: sdl.K_u}, MoveNorthEast},
+		{KeyChord{Key: sdl.K_b}, MoveSouthWest},
+		{KeyChord{Key: sdl.K_n}, MoveSouthEast},
+		{KeyChord{Key: sdl.K_PERIOD}, Wait},
+	},
+	"numpad": {
+		{KeyChord{Key: sdl.K_KP_4}, MoveWest},
+		{KeyChord{Key: sdl.K_KP_2}, MoveSouth},
+		{KeyChord{Key: sdl.K_KP_8}, MoveNorth},
+		{KeyChord{Key: sdl.K_KP_6}, MoveEast},
+		{KeyChord{Key: sdl.K_KP_7}, MoveNorthWest},
+		{KeyChord{Key: sdl.K_KP_9}, MoveNorthEast},
+		{KeyChord{Key: sdl.K_KP_1}, MoveSouthWest},
+		{KeyChord{Key: sdl.K_KP_3}, MoveSouthEast},
+		{KeyChord{Key: sdl.K_KP_5}, Wait},
+	},
+	"arrows": {
+		{KeyChord{Key: sdl.K_LEFT}, MoveWest},
+		{KeyChord{Key: sdl.K_DOWN}, MoveSouth},
+		{KeyChord{Key: sdl.K_UP}, MoveNorth},
+		{KeyChord{Key: sdl.K_RIGHT}, MoveEast},
+		{KeyChord{Key: sdl.K_HOME}, MoveNorthWest},
+		{KeyChord{Key: sdl.K_PAGEUP}, MoveNorthEast},
+		{KeyChord{Key: sdl.K_END}, MoveSouthWest},
+		{KeyChord{Key: sdl.K_PAGEDOWN}, MoveSouthEast},
+	},
+}
+
+// Presets lists the names of the built in presets
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddPreset binds every chord from the named preset: "vi", "numpad" or "arrows"
+func (keymap *Keymap) AddPreset(name string) error {
+	bindings, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("Unknown keymap preset %q", name)
+	}
+	for _, binding := range bindings {
+		keymap.Bind(binding.Chord, binding.Action)
+	}
+	return nil
+}
+
+// LoadKeymap reads a keymap config. Each line is either "preset <name>" or
+// "<Action> = <chord>", blank lines and lines starting with # are ignored.
+// Bind an action to several chords by repeating it on more lines.
+func LoadKeymap(r io.Reader) (*Keymap, error) {
+	keymap := NewKeymap()
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "preset ") {
+			if err := keymap.AddPreset(strings.TrimSpace(strings.TrimPrefix(line, "preset "))); err != nil {
+				return nil, fmt.Errorf("Line %v: %v", lineNumber, err)
+			}
+			continue
+		}
+
+		split := strings.Index(line, "=")
+		if split < 0 {
+			return nil, fmt.Errorf("Line %v: expected <Action> = <chord> but got %q", lineNumber, line)
+		}
+		action := Action(strings.TrimSpace(line[:split]))
+		chord, err := ParseChord(line[split+1:])
+		if err != nil {
+			return nil, fmt.Errorf("Line %v: %v", lineNumber, err)
+		}
+		keymap.Bind(chord, action)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return keymap, nil
+}
+
+// LoadKeymapFile reads a keymap config from path, see LoadKeymap
+func LoadKeymapFile(path string) (*Keymap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadKeymap(file)
+}
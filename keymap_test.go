@@ -0,0 +1,185 @@
+package gterm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected KeyChord
+	}{
+		{"g", KeyChord{Key: sdl.K_g}},
+		{"Shift+.", KeyChord{Key: sdl.K_PERIOD, Mod: ModShift}},
+		{"Shift+,", KeyChord{Key: sdl.K_COMMA, Mod: ModShift}},
+		{"Shift+/", KeyChord{Key: sdl.K_SLASH, Mod: ModShift}},
+		{"Shift++", KeyChord{Key: sdl.K_PLUS, Mod: ModShift}},
+		{"=", KeyChord{Key: sdl.K_EQUALS}},
+		{"-", KeyChord{Key: sdl.K_MINUS}},
+		{"Ctrl+Alt+x", KeyChord{Key: sdl.K_x, Mod: ModCtrl | ModAlt}},
+		{" ctrl + gui + X ", KeyChord{Key: sdl.K_x, Mod: ModCtrl | ModGUI}},
+		{"Keypad 7", KeyChord{Key: sdl.K_KP_7}},
+		{"Return", KeyChord{Key: sdl.K_RETURN}},
+	}
+	for _, test := range tests {
+		chord, err := ParseChord(test.text)
+		if err != nil {
+			t.Errorf("%q: %v", test.text, err)
+			continue
+		}
+		if chord != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.text, test.expected, chord)
+		}
+	}
+}
+
+func TestParseChordErrors(t *testing.T) {
+	for _, text := range []string{"", "Shift+", "Hyper+x", "Ctrl+NotAKey", "Shift+Ctrl"} {
+		if chord, err := ParseChord(text); err == nil {
+			t.Errorf("%q: expected an error, got %v", text, chord)
+		}
+	}
+}
+
+func TestChordStringRoundTrip(t *testing.T) {
+	for _, chord := range []KeyChord{
+		{Key: sdl.K_x, Mod: ModCtrl | ModShift},
+		{Key: sdl.K_PERIOD, Mod: ModShift},
+		{Key: sdl.K_KP_5},
+	} {
+		parsed, err := ParseChord(chord.String())
+		if err != nil || parsed != chord {
+			t.Errorf("%v: parsed back as %v %v", chord, parsed, err)
+		}
+	}
+}
+
+func TestResolveIgnoresModifierSide(t *testing.T) {
+	keymap := NewKeymap()
+	keymap.Bind(KeyChord{Key: sdl.K_PERIOD, Mod: ModShift}, "Descend")
+	keymap.BindKey(sdl.K_PERIOD, Wait)
+
+	for _, mod := range []sdl.Keymod{sdl.KMOD_LSHIFT, sdl.KMOD_RSHIFT, sdl.KMOD_LSHIFT | sdl.KMOD_NUM} {
+		if action, ok := keymap.Resolve(sdl.K_PERIOD, mod); !ok || action != "Descend" {
+			t.Errorf("Mod %v: expected Descend, got %q %v", mod, action, ok)
+		}
+	}
+	if action, ok := keymap.Resolve(sdl.K_PERIOD, 0); !ok || action != Wait {
+		t.Errorf("Expected Wait without shift, got %q %v", action, ok)
+	}
+	if _, ok := keymap.Resolve(sdl.K_PERIOD, sdl.KMOD_LCTRL); ok {
+		t.Error("Expected Ctrl+. to be unbound")
+	}
+
+	event := &sdl.KeyDownEvent{Keysym: sdl.Keysym{Sym: sdl.K_PERIOD, Mod: sdl.KMOD_RSHIFT}}
+	if action, ok := keymap.Lookup(event); !ok || action != "Descend" {
+		t.Errorf("Expected the key event to look up Descend, got %q %v", action, ok)
+	}
+	if _, ok := keymap.Lookup(&sdl.KeyUpEvent{Keysym: event.Keysym}); ok {
+		t.Error("Expected key up events not to match")
+	}
+}
+
+func TestLoadKeymapOverridesPresets(t *testing.T) {
+	config := `
+# vi keys, but h inspects and waiting moves to w
+preset vi
+Inspect = h
+Wait = w
+Wait = Keypad 5
+`
+	keymap, err := LoadKeymap(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key    sdl.Keycode
+		action Action
+	}{
+		{sdl.K_h, "Inspect"},
+		{sdl.K_j, MoveSouth},
+		{sdl.K_PERIOD, Wait},
+		{sdl.K_w, Wait},
+	}
+	for _, test := range tests {
+		if action, ok := keymap.Resolve(test.key, 0); !ok || action != test.action {
+			t.Errorf("%v: expected %v, got %q %v", sdl.GetKeyName(test.key), test.action, action, ok)
+		}
+	}
+
+	expected := []KeyChord{{Key: sdl.K_PERIOD}, {Key: sdl.K_w}, {Key: sdl.K_KP_5}}
+	if chords := keymap.ChordsFor(Wait); !reflect.DeepEqual(chords, expected) {
+		t.Errorf("Expected Wait on %v, got %v", expected, chords)
+	}
+
+	keymap.Unbind(KeyChord{Key: sdl.K_w})
+	if _, ok := keymap.Resolve(sdl.K_w, 0); ok {
+		t.Error("Expected w to be unbound")
+	}
+	for _, binding := range keymap.Bindings() {
+		if binding.Chord.Key == sdl.K_w {
+			t.Error("Expected w gone from the bindings")
+		}
+	}
+}
+
+func TestLoadKeymapErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{"preset emacs", "Line 1"},
+		{"\nWait", "Line 2"},
+		{"Wait = Hyper+w", "Unknown modifier"},
+		{"Wait = Nowhere", "Unknown key"},
+	}
+	for _, test := range tests {
+		_, err := LoadKeymap(strings.NewReader(test.config))
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%q: expected an error mentioning %q, got %v", test.config, test.err, err)
+		}
+	}
+}
+
+func TestLoadKeymapFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gterm-keymap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keys.cfg")
+	if err := ioutil.WriteFile(path, []byte("preset arrows\nMoveNorth = Keypad 8\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keymap, err := LoadKeymapFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []sdl.Keycode{sdl.K_UP, sdl.K_KP_8} {
+		if action, ok := keymap.Resolve(key, 0); !ok || action != MoveNorth {
+			t.Errorf("%v: expected MoveNorth, got %q %v", sdl.GetKeyName(key), action, ok)
+		}
+	}
+
+	if _, err := LoadKeymapFile(filepath.Join(dir, "missing.cfg")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	if names := Presets(); !reflect.DeepEqual(names, []string{"arrows", "numpad", "vi"}) {
+		t.Errorf("Expected the three presets in order, got %v", names)
+	}
+	if err := NewKeymap().AddPreset("emacs"); err == nil {
+		t.Error("Expected an unknown preset to fail")
+	}
+}
@@ -0,0 +1,66 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Each cell has a light colour that multiplies its foreground and background
+// colours when the frame is composed, white leaves them as they are and black
+// hides them. Cells start out without a light, which draws them as put. Like
+// the background colour, a cell's light is reset by ClearCell and ClearWindow.
+
+// SetLight sets the light falling on a cell, NoColor removes it
+func (window *Window) SetLight(col int, row int, light sdl.Color) error {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return err
+	}
+	window.cells[index].light = light
+	return nil
+}
+
+// AddLight adds to the light falling on a cell, so overlapping lights brighten
+// each other. A cell without a light starts from darkness.
+func (window *Window) AddLight(col int, row int, light sdl.Color) error {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return err
+	}
+	current := window.cells[index].light
+	window.cells[index].light = sdl.Color{
+		R: addChannel(current.R, light.R),
+		G: addChannel(current.G, light.G),
+		B: addChannel(current.B, light.B),
+		A: 255,
+	}
+	return nil
+}
+
+// Light returns the light falling on a cell, NoColor when it has none
+func (window *Window) Light(col int, row int) (sdl.Color, error) {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return NoColor, err
+	}
+	return window.cells[index].light, nil
+}
+
+func addChannel(a uint8, b uint8) uint8 {
+	if sum := int(a) + int(b); sum < 255 {
+		return uint8(sum)
+	}
+	return 255
+}
+
+// applyLight multiplies a colour by a light, keeping the colour's alpha
+func applyLight(color sdl.Color, light sdl.Color) sdl.Color {
+	if light == NoColor || color == NoColor {
+		return color
+	}
+	return sdl.Color{
+		R: uint8(int(color.R) * int(light.R) / 255),
+		G: uint8(int(color.G) * int(light.G) / 255),
+		B: uint8(int(color.B) * int(light.B) / 255),
+		A: color.A,
+	}
+}
@@ -0,0 +1,39 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestApplyLight(t *testing.T) {
+	color := sdl.Color{R: 200, G: 100, B: 50, A: 255}
+
+	cases := []struct {
+		light    sdl.Color
+		expected sdl.Color
+	}{
+		{light: NoColor, expected: color},
+		{light: sdl.Color{R: 255, G: 255, B: 255, A: 255}, expected: color},
+		{light: sdl.Color{R: 0, G: 0, B: 0, A: 255}, expected: sdl.Color{A: 255}},
+		{light: sdl.Color{R: 128, G: 255, B: 0, A: 255}, expected: sdl.Color{R: 100, G: 100, B: 0, A: 255}},
+	}
+	for _, c := range cases {
+		if lit := applyLight(color, c.light); lit != c.expected {
+			t.Errorf("applyLight(%v, %v) = %v, expected %v", color, c.light, lit, c.expected)
+		}
+	}
+
+	if lit := applyLight(NoColor, sdl.Color{R: 128, A: 255}); lit != NoColor {
+		t.Errorf("Expected NoColor to stay unlit, got %v", lit)
+	}
+}
+
+func TestAddChannelSaturates(t *testing.T) {
+	if sum := addChannel(200, 100); sum != 255 {
+		t.Errorf("Expected 255, got %v", sum)
+	}
+	if sum := addChannel(20, 100); sum != 120 {
+		t.Errorf("Expected 120, got %v", sum)
+	}
+}
@@ -0,0 +1,200 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// App is driven by Window.Run. Events, updates and rendering are delivered
+// through separate callbacks so an app never has to draw while handling input.
+type App interface {
+	// HandleEvent receives every event the window collects
+	HandleEvent(event sdl.Event)
+	// Update advances the app by delta milliseconds
+	Update(delta uint32)
+	// Render draws the app into the window, Run takes care of refreshing
+	Render(window *Window)
+	// Animating reports whether the app needs redrawing without new input
+	Animating() bool
+	// Done reports whether Run should return
+	Done() bool
+}
+
+// LoopMode selects how Run paces updates and redraws
+type LoopMode int
+
+const (
+	// TurnBased blocks waiting for input and only redraws when an event
+	// arrives or something is animating.
+	TurnBased LoopMode = iota
+	// RealTime updates at a fixed timestep and redraws every frame.
+	RealTime
+)
+
+// RunOptions configures Window.Run
+type RunOptions struct {
+	Mode LoopMode
+
+	// Step is the fixed update length in milliseconds for RealTime mode and
+	// the frame length while animating in TurnBased mode. Defaults to 16.
+	Step uint32
+
+	// IdleTimeout is how long TurnBased mode waits for input, in
+	// milliseconds, before checking whether it should stop. Defaults to 250.
+	IdleTimeout int
+
+	// MaxUpdates caps how many fixed steps RealTime mode runs per frame so a
+	// slow frame can't spiral. Defaults to 5.
+	MaxUpdates int
+}
+
+func (options *RunOptions) setDefaults() {
+	if options.Step == 0 {
+		options.Step = 16
+	}
+	if options.IdleTimeout == 0 {
+		options.IdleTimeout = 250
+	}
+	if options.MaxUpdates == 0 {
+		options.MaxUpdates = 5
+	}
+}
+
+// Run drives app until it reports it is done
+func (window *Window) Run(app App, options RunOptions) {
+	options.setDefaults()
+
+	switch options.Mode {
+	case TurnBased:
+		window.runTurnBased(app, options)
+	case RealTime:
+		window.runRealTime(app, options)
+	}
+}
+
+// redrawing reports whether anything besides animated cells needs drawing every step
+func (window *Window) redrawing(app App) bool {
+	return app.Animating() || window.Effecting() || window.floatingMoving
+}
+
+// animationDue reports whether something animating needs drawing now
+func (window *Window) animationDue(app App) bool {
+	if window.redrawing(app) {
+		return true
+	}
+	return window.animatedCells > 0 && int32(sdl.GetTicks()-window.nextAnimationTick) >= 0
+}
+
+// waitTimeout is how long the turn based loop can wait for input before
+// something animating needs drawing again
+func (window *Window) waitTimeout(app App, options RunOptions) int {
+	if window.redrawing(app) {
+		return int(options.Step)
+	}
+	if window.animatedCells == 0 {
+		return options.IdleTimeout
+	}
+
+	// Only cells are animating, sleep until the next of them changes
+	wait := int(int32(window.nextAnimationTick - sdl.GetTicks()))
+	if wait < 1 {
+		return 1
+	}
+	if wait > options.IdleTimeout {
+		return options.IdleTimeout
+	}
+	return wait
+}
+
+// pollEvents hands every queued event to the app and reports whether there were any
+func (window *Window) pollEvents(app App) bool {
+	events := window.EventSource()
+	handled := window.deliverHits(app)
+	for event := events.PollEvent(); event != nil; event = events.PollEvent() {
+		window.deliver(app, event)
+		handled = true
+	}
+	return handled
+}
+
+// deliver hands an event to the app followed by the hit events it causes
+func (window *Window) deliver(app App, event sdl.Event) {
+	app.HandleEvent(event)
+	window.trackMouse(event)
+	window.deliverHits(app)
+}
+
+// deliverHits hands queued hit events to the app and reports whether there were any
+func (window *Window) deliverHits(app App) bool {
+	hits := window.hits.flush()
+	for _, hit := range hits {
+		app.HandleEvent(hit)
+	}
+	return len(hits) > 0
+}
+
+func (window *Window) render(app App) {
+	app.Render(window)
+	window.Refresh()
+}
+
+func (window *Window) runTurnBased(app App, options RunOptions) {
+	lastTicks := sdl.GetTicks()
+	app.Update(0)
+	window.render(app)
+
+	for !app.Done() {
+		timeout := window.waitTimeout(app, options)
+
+		redraw := false
+		if event := window.EventSource().WaitEventTimeout(timeout); event != nil {
+			window.deliver(app, event)
+			redraw = true
+		}
+		if window.pollEvents(app) {
+			redraw = true
+		}
+
+		if !redraw && !window.animationDue(app) {
+			continue
+		}
+
+		now := sdl.GetTicks()
+		app.Update(now - lastTicks)
+		lastTicks = now
+
+		window.render(app)
+	}
+}
+
+func (window *Window) runRealTime(app App, options RunOptions) {
+	lastTicks := sdl.GetTicks()
+	var accumulated uint32
+
+	for !app.Done() {
+		window.pollEvents(app)
+
+		now := sdl.GetTicks()
+		accumulated += now - lastTicks
+		lastTicks = now
+
+		for updates := 0; accumulated >= options.Step; updates++ {
+			if updates == options.MaxUpdates {
+				// Too far behind to catch up, drop the backlog
+				accumulated = 0
+				break
+			}
+			app.Update(options.Step)
+			accumulated -= options.Step
+		}
+
+		window.render(app)
+
+		if !window.vsync {
+			// Without vsync to pace us sleep until the next step is due
+			elapsed := sdl.GetTicks() - lastTicks + accumulated
+			if elapsed < options.Step {
+				sdl.Delay(options.Step - elapsed)
+			}
+		}
+	}
+}
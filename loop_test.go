@@ -0,0 +1,161 @@
+package gterm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// newTestWindow draws offscreen into a software renderer with the 8x8 CP437
+// sheet, so tests need no display. Call the returned func when done.
+func newTestWindow(t *testing.T, columns int, rows int) (*Window, func()) {
+	window := NewWindow(columns, rows, filepath.Join("example", "atlas", "fonts", "cp437_8x8.png"), 8, 8, false)
+	surface, err := sdl.CreateRGBSurface(0, int32(columns*8), int32(rows*8), 32, 0x000000ff, 0x0000ff00, 0x00ff0000, 0xff000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		t.Fatal(err)
+	}
+	window.SdlRenderer = renderer
+	if window.fontSheet, err = window.loadFont(window.FontWPixel); err != nil {
+		renderer.Destroy()
+		surface.Free()
+		t.Fatal(err)
+	}
+	return window, func() {
+		window.fontSheet.Destroy()
+		renderer.Destroy()
+		surface.Free()
+	}
+}
+
+// scriptedEvents hands out one step of its script per wait, a nil step is a
+// wait that timed out. It remembers how long each wait was allowed to take.
+type scriptedEvents struct {
+	script []sdl.Event
+	waits  []int
+}
+
+func (source *scriptedEvents) PollEvent() sdl.Event {
+	return nil
+}
+
+func (source *scriptedEvents) WaitEventTimeout(timeout int) sdl.Event {
+	source.waits = append(source.waits, timeout)
+	if len(source.script) == 0 {
+		return nil
+	}
+	event := source.script[0]
+	source.script = source.script[1:]
+	return event
+}
+
+// loopApp counts what Run asks of it, it is done once done says so
+type loopApp struct {
+	events    int
+	renders   int
+	updates   []uint32
+	animating bool
+	done      func(app *loopApp) bool
+}
+
+func (app *loopApp) HandleEvent(event sdl.Event) { app.events++ }
+func (app *loopApp) Update(delta uint32)         { app.updates = append(app.updates, delta) }
+func (app *loopApp) Render(window *Window)       { app.renders++ }
+func (app *loopApp) Animating() bool             { return app.animating }
+func (app *loopApp) Done() bool                  { return app.done(app) }
+
+func TestTurnBasedRedrawsOnEvents(t *testing.T) {
+	window, destroy := newTestWindow(t, 4, 4)
+	defer destroy()
+
+	source := &scriptedEvents{script: []sdl.Event{&sdl.KeyDownEvent{}, nil, nil, &sdl.KeyDownEvent{}}}
+	window.events = source
+	app := &loopApp{done: func(app *loopApp) bool { return len(source.script) == 0 }}
+	window.Run(app, RunOptions{Mode: TurnBased})
+
+	if app.events != 2 {
+		t.Errorf("Expected 2 events, got %v", app.events)
+	}
+	// Once to start and once per event, timeouts alone don't redraw
+	if app.renders != 3 || len(app.updates) != 3 {
+		t.Errorf("Expected 3 renders and updates, got %v and %v", app.renders, len(app.updates))
+	}
+	if app.updates[0] != 0 {
+		t.Errorf("Expected the first update to be empty, got %v", app.updates[0])
+	}
+	for _, wait := range source.waits {
+		if wait != 250 {
+			t.Errorf("Expected idle waits of the default 250ms, got %v", source.waits)
+			break
+		}
+	}
+}
+
+func TestTurnBasedWaitsOneStepWhileAnimating(t *testing.T) {
+	window, destroy := newTestWindow(t, 4, 4)
+	defer destroy()
+
+	source := &scriptedEvents{}
+	window.events = source
+	app := &loopApp{animating: true, done: func(app *loopApp) bool { return app.renders == 4 }}
+	window.Run(app, RunOptions{Mode: TurnBased, Step: 10})
+
+	// Animating apps redraw without events
+	if app.events != 0 || len(source.waits) != 3 {
+		t.Fatalf("Expected 3 empty waits, got %v waits and %v events", len(source.waits), app.events)
+	}
+	for _, wait := range source.waits {
+		if wait != 10 {
+			t.Errorf("Expected waits of one step, got %v", source.waits)
+			break
+		}
+	}
+}
+
+func TestRunExitsWhenDone(t *testing.T) {
+	for _, mode := range []LoopMode{TurnBased, RealTime} {
+		window, destroy := newTestWindow(t, 4, 4)
+
+		source := &scriptedEvents{script: []sdl.Event{&sdl.KeyDownEvent{}}}
+		window.events = source
+		app := &loopApp{done: func(app *loopApp) bool { return true }}
+		window.Run(app, RunOptions{Mode: mode})
+
+		if app.events != 0 || len(source.waits) != 0 {
+			t.Errorf("Mode %v: expected no events once done, got %v", mode, app.events)
+		}
+		destroy()
+	}
+}
+
+func TestRealTimeUpdatesInFixedSteps(t *testing.T) {
+	window, destroy := newTestWindow(t, 4, 4)
+	defer destroy()
+
+	source := &scriptedEvents{}
+	window.events = source
+	app := &loopApp{done: func(app *loopApp) bool { return len(app.updates) >= 10 }}
+	start := sdl.GetTicks()
+	window.Run(app, RunOptions{Mode: RealTime, Step: 5})
+	elapsed := sdl.GetTicks() - start
+
+	for _, delta := range app.updates {
+		if delta != 5 {
+			t.Fatalf("Expected every update to be one 5ms step, got %v", app.updates)
+		}
+	}
+	if elapsed < 45 {
+		t.Errorf("Expected 10 steps to take at least 45ms, took %v", elapsed)
+	}
+	if app.renders == 0 {
+		t.Error("Expected frames to be drawn")
+	}
+	if len(source.waits) != 0 {
+		t.Errorf("Expected real time mode to poll rather than wait, got %v waits", len(source.waits))
+	}
+}
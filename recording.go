@@ -0,0 +1,307 @@
+package gterm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// EventSource is where Run gets its events from
+type EventSource interface {
+	PollEvent() sdl.Event
+	WaitEventTimeout(timeout int) sdl.Event
+}
+
+// sdlEvents reads events from SDL's queue
+type sdlEvents struct{}
+
+func (sdlEvents) PollEvent() sdl.Event {
+	return sdl.PollEvent()
+}
+
+func (sdlEvents) WaitEventTimeout(timeout int) sdl.Event {
+	return sdl.WaitEventTimeout(timeout)
+}
+
+// SetEventSource replaces where Run gets its events, nil goes back to SDL
+func (window *Window) SetEventSource(source EventSource) {
+	window.events = source
+}
+
+// EventSource returns where Run currently gets its events
+func (window *Window) EventSource() EventSource {
+	if window.events == nil {
+		return sdlEvents{}
+	}
+	return window.events
+}
+
+// RecordEvents writes every event Run delivers from now on to w
+func (window *Window) RecordEvents(w io.Writer) *EventRecorder {
+	recorder := NewEventRecorder(window.EventSource(), w)
+	window.SetEventSource(recorder)
+	return recorder
+}
+
+// PlayEvents feeds Run the events recorded in r instead of live input. Live
+// events take over again once the recording runs out.
+func (window *Window) PlayEvents(r io.Reader, mode PlaybackMode) (*EventPlayer, error) {
+	player, err := NewEventPlayer(r, mode)
+	if err != nil {
+		return nil, err
+	}
+	player.Live = window.EventSource()
+	window.SetEventSource(player)
+	return player, nil
+}
+
+// recordedEvent is one line of a recording
+type recordedEvent struct {
+	// Ticks is milliseconds since recording started
+	Ticks uint32
+	Type  string
+	Event json.RawMessage
+}
+
+// newRecordableEvent returns an empty event of a recorded type, ok is false for
+// types that aren't recorded
+func newRecordableEvent(kind string) (event sdl.Event, ok bool) {
+	switch kind {
+	case "KeyDown":
+		return &sdl.KeyDownEvent{}, true
+	case "KeyUp":
+		return &sdl.KeyUpEvent{}, true
+	case "TextInput":
+		return &sdl.TextInputEvent{}, true
+	case "MouseMotion":
+		return &sdl.MouseMotionEvent{}, true
+	case "MouseButton":
+		return &sdl.MouseButtonEvent{}, true
+	case "MouseWheel":
+		return &sdl.MouseWheelEvent{}, true
+	case "Window":
+		return &sdl.WindowEvent{}, true
+	case "Quit":
+		return &sdl.QuitEvent{}, true
+	}
+	return nil, false
+}
+
+func recordedType(event sdl.Event) (kind string, ok bool) {
+	switch event.(type) {
+	case *sdl.KeyDownEvent:
+		return "KeyDown", true
+	case *sdl.KeyUpEvent:
+		return "KeyUp", true
+	case *sdl.TextInputEvent:
+		return "TextInput", true
+	case *sdl.MouseMotionEvent:
+		return "MouseMotion", true
+	case *sdl.MouseButtonEvent:
+		return "MouseButton", true
+	case *sdl.MouseWheelEvent:
+		return "MouseWheel", true
+	case *sdl.WindowEvent:
+		return "Window", true
+	case *sdl.QuitEvent:
+		return "Quit", true
+	}
+	return "", false
+}
+
+// EventRecorder passes events through from another source, writing each one
+// to a recording as a line of JSON with the time it arrived. Events of types
+// that can't be played back are passed through without being recorded.
+type EventRecorder struct {
+	source  EventSource
+	encoder *json.Encoder
+	start   uint32
+	err     error
+}
+
+// NewEventRecorder records the events read from source to w
+func NewEventRecorder(source EventSource, w io.Writer) *EventRecorder {
+	return &EventRecorder{
+		source:  source,
+		encoder: json.NewEncoder(w),
+		start:   sdl.GetTicks(),
+	}
+}
+
+func (recorder *EventRecorder) record(event sdl.Event) sdl.Event {
+	kind, ok := recordedType(event)
+	if !ok || recorder.err != nil {
+		return event
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		recorder.err = err
+		return event
+	}
+	recorder.err = recorder.encoder.Encode(recordedEvent{
+		Ticks: sdl.GetTicks() - recorder.start,
+		Type:  kind,
+		Event: data,
+	})
+	return event
+}
+
+func (recorder *EventRecorder) PollEvent() sdl.Event {
+	if event := recorder.source.PollEvent(); event != nil {
+		return recorder.record(event)
+	}
+	return nil
+}
+
+func (recorder *EventRecorder) WaitEventTimeout(timeout int) sdl.Event {
+	if event := recorder.source.WaitEventTimeout(timeout); event != nil {
+		return recorder.record(event)
+	}
+	return nil
+}
+
+// Err returns the first error writing the recording, recording stops after it
+func (recorder *EventRecorder) Err() error {
+	return recorder.err
+}
+
+// PlaybackMode selects how quickly a recording is played back
+type PlaybackMode int
+
+const (
+	// PlaybackRealTime delivers each event as long after playback started as it arrived after recording started
+	PlaybackRealTime PlaybackMode = iota
+	// PlaybackFast delivers events without waiting, one per frame so the app
+	// still updates and renders between them
+	PlaybackFast
+)
+
+type playbackEvent struct {
+	ticks uint32
+	event sdl.Event
+}
+
+// EventPlayer is an event source that plays back a recording. While it plays
+// only quit events get through from Live, so the window can still be closed.
+// Once it is finished every event comes from Live.
+type EventPlayer struct {
+	Mode PlaybackMode
+	Live EventSource
+
+	events []playbackEvent
+	next   int
+	start  uint32
+
+	// delivered is set once this frame's event has gone in PlaybackFast mode
+	delivered bool
+}
+
+// NewEventPlayer reads a recording written by an EventRecorder
+func NewEventPlayer(r io.Reader, mode PlaybackMode) (*EventPlayer, error) {
+	player := &EventPlayer{Mode: mode, start: sdl.GetTicks()}
+
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var recorded recordedEvent
+		if err := decoder.Decode(&recorded); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to read recorded event %v: %v", line, err)
+		}
+
+		event, ok := newRecordableEvent(recorded.Type)
+		if !ok {
+			return nil, fmt.Errorf("Unknown recorded event type %q on event %v", recorded.Type, line)
+		}
+		if err := json.Unmarshal(recorded.Event, event); err != nil {
+			return nil, fmt.Errorf("Failed to read recorded %v event %v: %v", recorded.Type, line, err)
+		}
+		player.events = append(player.events, playbackEvent{ticks: recorded.Ticks, event: event})
+	}
+
+	return player, nil
+}
+
+// Finished reports whether every recorded event has been played
+func (player *EventPlayer) Finished() bool {
+	return player.next >= len(player.events)
+}
+
+// due returns how many milliseconds until the next event should be played
+func (player *EventPlayer) due() int {
+	if player.Mode == PlaybackFast {
+		return 0
+	}
+	elapsed := sdl.GetTicks() - player.start
+	if wanted := player.events[player.next].ticks; wanted > elapsed {
+		return int(wanted - elapsed)
+	}
+	return 0
+}
+
+func (player *EventPlayer) take() sdl.Event {
+	event := player.events[player.next].event
+	player.next++
+	return event
+}
+
+// liveQuit drains the live source during playback, passing on quit events only
+func (player *EventPlayer) liveQuit() sdl.Event {
+	if player.Live == nil {
+		return nil
+	}
+	for event := player.Live.PollEvent(); event != nil; event = player.Live.PollEvent() {
+		if _, ok := event.(*sdl.QuitEvent); ok {
+			return event
+		}
+	}
+	return nil
+}
+
+func (player *EventPlayer) PollEvent() sdl.Event {
+	if player.Finished() {
+		if player.Live == nil {
+			return nil
+		}
+		return player.Live.PollEvent()
+	}
+	if quit := player.liveQuit(); quit != nil {
+		return quit
+	}
+	if player.Mode == PlaybackFast {
+		// Running out of events ends the frame's poll loop
+		if player.delivered {
+			player.delivered = false
+			return nil
+		}
+		player.delivered = true
+	} else if player.due() > 0 {
+		return nil
+	}
+	return player.take()
+}
+
+func (player *EventPlayer) WaitEventTimeout(timeout int) sdl.Event {
+	if player.Finished() {
+		if player.Live == nil {
+			sdl.Delay(uint32(timeout))
+			return nil
+		}
+		return player.Live.WaitEventTimeout(timeout)
+	}
+	if quit := player.liveQuit(); quit != nil {
+		return quit
+	}
+	wait := player.due()
+	if wait > timeout {
+		sdl.Delay(uint32(timeout))
+		return nil
+	}
+	if wait > 0 {
+		sdl.Delay(uint32(wait))
+	}
+	player.delivered = true
+	return player.take()
+}
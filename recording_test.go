@@ -0,0 +1,86 @@
+package gterm
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// queuedEvents is an event source that hands out a fixed list of events
+type queuedEvents struct {
+	events []sdl.Event
+}
+
+func (queue *queuedEvents) PollEvent() sdl.Event {
+	if len(queue.events) == 0 {
+		return nil
+	}
+	event := queue.events[0]
+	queue.events = queue.events[1:]
+	return event
+}
+
+func (queue *queuedEvents) WaitEventTimeout(timeout int) sdl.Event {
+	return queue.PollEvent()
+}
+
+func TestRecordAndPlayBack(t *testing.T) {
+	text := &sdl.TextInputEvent{WindowID: 1}
+	copy(text.Text[:], "hi")
+	events := []sdl.Event{
+		&sdl.KeyDownEvent{WindowID: 1, Keysym: sdl.Keysym{Sym: sdl.K_x, Mod: 1}},
+		text,
+		&sdl.MouseButtonEvent{Button: 1, State: 1, X: 40, Y: 20},
+		&sdl.QuitEvent{},
+	}
+
+	var recording bytes.Buffer
+	recorder := NewEventRecorder(&queuedEvents{events: append([]sdl.Event(nil), events...)}, &recording)
+	for event := recorder.PollEvent(); event != nil; event = recorder.PollEvent() {
+	}
+	if err := recorder.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := NewEventPlayer(&recording, PlaybackFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range events {
+		event := player.WaitEventTimeout(0)
+		if !reflect.DeepEqual(event, expected) {
+			t.Errorf("Event %v played back as %#v, expected %#v", i, event, expected)
+		}
+	}
+	if !player.Finished() {
+		t.Error("Expected the player to be finished")
+	}
+}
+
+func TestFastPlaybackPollsOneEventPerFrame(t *testing.T) {
+	recording := `{"Ticks":0,"Type":"Quit","Event":{}}
+{"Ticks":5,"Type":"Quit","Event":{}}
+`
+	player, err := NewEventPlayer(strings.NewReader(recording), PlaybackFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.PollEvent() == nil {
+		t.Fatal("Expected the first poll to deliver an event")
+	}
+	if player.PollEvent() != nil {
+		t.Fatal("Expected the second poll to end the frame")
+	}
+	if player.PollEvent() == nil {
+		t.Fatal("Expected the next frame to deliver an event")
+	}
+}
+
+func TestPlayerRejectsUnknownEvents(t *testing.T) {
+	if _, err := NewEventPlayer(strings.NewReader(`{"Ticks":0,"Type":"Joystick","Event":{}}`), PlaybackFast); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
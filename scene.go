@@ -0,0 +1,171 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Scene is one screen of an application, a title screen, the game itself or a
+// popup over it. Scenes are kept on a SceneStack which drives them.
+type Scene interface {
+	// Enter is called when the scene is pushed onto scenes
+	Enter(scenes *SceneStack)
+	// Exit is called when the scene is popped or replaced
+	Exit()
+	// HandleEvent receives events while the scene is on top, or below scenes that aren't modal
+	HandleEvent(event sdl.Event)
+	// Update advances the scene by delta milliseconds, it is delivered like events
+	Update(delta uint32)
+	// Render draws the scene, scenes below transparent ones are drawn first
+	Render(window *Window)
+}
+
+// TransparentScene is a scene drawn over the scene below it rather than instead
+// of it, like a popup
+type TransparentScene interface {
+	Transparent() bool
+}
+
+// ModalScene decides whether the scenes below it see events and updates.
+// Scenes without a Modal method capture everything.
+type ModalScene interface {
+	Modal() bool
+}
+
+// AnimatedScene is a scene that needs redrawing without new input, see App
+type AnimatedScene interface {
+	Animating() bool
+}
+
+// SceneStack drives a stack of scenes. It implements App so it can be handed
+// straight to Window.Run, and is done once it is empty or Quit is called. A
+// quit event from the window quits the stack.
+type SceneStack struct {
+	scenes []Scene
+	quit   bool
+}
+
+// NewSceneStack constructs a stack starting with scene on it
+func NewSceneStack(scene Scene) *SceneStack {
+	scenes := &SceneStack{}
+	scenes.Push(scene)
+	return scenes
+}
+
+// Push puts scene on top of the stack and enters it
+func (scenes *SceneStack) Push(scene Scene) {
+	scenes.scenes = append(scenes.scenes, scene)
+	scene.Enter(scenes)
+}
+
+// Pop exits and removes the top scene, returning it. It returns nil when the stack is empty.
+func (scenes *SceneStack) Pop() Scene {
+	if len(scenes.scenes) == 0 {
+		return nil
+	}
+	top := scenes.scenes[len(scenes.scenes)-1]
+	scenes.scenes = scenes.scenes[:len(scenes.scenes)-1]
+	top.Exit()
+	return top
+}
+
+// Replace swaps the top scene for scene
+func (scenes *SceneStack) Replace(scene Scene) {
+	scenes.Pop()
+	scenes.Push(scene)
+}
+
+// Top returns the scene on top of the stack, nil when it is empty
+func (scenes *SceneStack) Top() Scene {
+	if len(scenes.scenes) == 0 {
+		return nil
+	}
+	return scenes.scenes[len(scenes.scenes)-1]
+}
+
+// Len is how many scenes are on the stack
+func (scenes *SceneStack) Len() int {
+	return len(scenes.scenes)
+}
+
+// Quit exits every scene and finishes the stack
+func (scenes *SceneStack) Quit() {
+	for len(scenes.scenes) > 0 {
+		scenes.Pop()
+	}
+	scenes.quit = true
+}
+
+func isModal(scene Scene) bool {
+	if modal, ok := scene.(ModalScene); ok {
+		return modal.Modal()
+	}
+	return true
+}
+
+func isTransparent(scene Scene) bool {
+	if transparent, ok := scene.(TransparentScene); ok {
+		return transparent.Transparent()
+	}
+	return false
+}
+
+// receivers are the scenes that get input, from the top down to the first modal one
+func (scenes *SceneStack) receivers() []Scene {
+	var receivers []Scene
+	for i := len(scenes.scenes) - 1; i >= 0; i-- {
+		receivers = append(receivers, scenes.scenes[i])
+		if isModal(scenes.scenes[i]) {
+			break
+		}
+	}
+	return receivers
+}
+
+// visible are the scenes that get drawn, from the first opaque one up to the top
+func (scenes *SceneStack) visible() []Scene {
+	bottom := len(scenes.scenes) - 1
+	for bottom > 0 && isTransparent(scenes.scenes[bottom]) {
+		bottom--
+	}
+	if bottom < 0 {
+		return nil
+	}
+	return scenes.scenes[bottom:]
+}
+
+func (scenes *SceneStack) HandleEvent(event sdl.Event) {
+	if _, ok := event.(*sdl.QuitEvent); ok {
+		scenes.Quit()
+		return
+	}
+	for _, scene := range scenes.receivers() {
+		scene.HandleEvent(event)
+	}
+}
+
+func (scenes *SceneStack) Update(delta uint32) {
+	for _, scene := range scenes.receivers() {
+		scene.Update(delta)
+	}
+}
+
+// Render clears the window and draws the visible scenes from the bottom up
+func (scenes *SceneStack) Render(window *Window) {
+	window.ClearWindow()
+	for _, scene := range scenes.visible() {
+		scene.Render(window)
+	}
+}
+
+func (scenes *SceneStack) Animating() bool {
+	for _, scene := range scenes.visible() {
+		if animated, ok := scene.(AnimatedScene); ok && animated.Animating() {
+			return true
+		}
+	}
+	return false
+}
+
+func (scenes *SceneStack) Done() bool {
+	return scenes.quit || len(scenes.scenes) == 0
+}
@@ -0,0 +1,93 @@
+package gterm
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type testScene struct {
+	name        string
+	transparent bool
+	passthrough bool
+
+	log *[]string
+}
+
+func (scene *testScene) record(what string) {
+	*scene.log = append(*scene.log, scene.name+" "+what)
+}
+
+func (scene *testScene) Enter(scenes *SceneStack)    { scene.record("enter") }
+func (scene *testScene) Exit()                       { scene.record("exit") }
+func (scene *testScene) HandleEvent(event sdl.Event) { scene.record("event") }
+func (scene *testScene) Update(delta uint32)         { scene.record("update") }
+func (scene *testScene) Render(window *Window)       { scene.record("render") }
+func (scene *testScene) Transparent() bool           { return scene.transparent }
+func (scene *testScene) Modal() bool                 { return !scene.passthrough }
+
+func expectLog(t *testing.T, log *[]string, expected ...string) {
+	t.Helper()
+	if len(*log) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, *log)
+	}
+	for i := range expected {
+		if (*log)[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, *log)
+		}
+	}
+	*log = (*log)[:0]
+}
+
+func TestSceneStackPushPopReplace(t *testing.T) {
+	var log []string
+	scenes := NewSceneStack(&testScene{name: "title", log: &log})
+	scenes.Push(&testScene{name: "options", log: &log})
+	expectLog(t, &log, "title enter", "options enter")
+
+	scenes.Pop()
+	scenes.Replace(&testScene{name: "game", log: &log})
+	expectLog(t, &log, "options exit", "title exit", "game enter")
+
+	if scenes.Len() != 1 || scenes.Top().(*testScene).name != "game" {
+		t.Errorf("Expected only the game scene left, got %v scenes", scenes.Len())
+	}
+
+	scenes.Quit()
+	expectLog(t, &log, "game exit")
+	if !scenes.Done() {
+		t.Error("Expected the stack to be done after quitting")
+	}
+}
+
+func TestSceneStackOverlays(t *testing.T) {
+	var log []string
+	scenes := NewSceneStack(&testScene{name: "title", log: &log})
+	scenes.Push(&testScene{name: "game", log: &log})
+	scenes.Push(&testScene{name: "popup", transparent: true, log: &log})
+	log = log[:0]
+
+	scenes.Render(&Window{})
+	expectLog(t, &log, "game render", "popup render")
+
+	scenes.HandleEvent(&sdl.KeyDownEvent{})
+	scenes.Update(16)
+	expectLog(t, &log, "popup event", "popup update")
+
+	scenes.Push(&testScene{name: "toast", transparent: true, passthrough: true, log: &log})
+	log = log[:0]
+	scenes.HandleEvent(&sdl.KeyDownEvent{})
+	expectLog(t, &log, "toast event", "popup event")
+}
+
+func TestSceneStackQuitEvent(t *testing.T) {
+	var log []string
+	scenes := NewSceneStack(&testScene{name: "game", log: &log})
+	log = log[:0]
+
+	scenes.HandleEvent(&sdl.QuitEvent{})
+	expectLog(t, &log, "game exit")
+	if !scenes.Done() {
+		t.Error("Expected a quit event to finish the stack")
+	}
+}
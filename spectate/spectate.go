@@ -0,0 +1,260 @@
+// Package spectate lets read-only observers watch a running window. Frames are
+// streamed as ANSI, for watching in a terminal, or as JSON cell deltas, for
+// tools and replay viewers. The game never waits on a spectator, slow ones skip
+// frames and catch up with the latest.
+package spectate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/thomas-holmes/gterm/telnet"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Format is how frames are written to a spectator
+type Format int
+
+const (
+	// ANSI draws frames with terminal escape codes, after the first frame
+	// only changed cells are sent
+	ANSI Format = iota
+	// JSON writes a Delta per line
+	JSON
+)
+
+// Delta is one line of the JSON stream
+type Delta struct {
+	Columns    int
+	Rows       int
+	Background string
+	// Full is set when Cells lists every cell, as in the first delta and after a resize
+	Full  bool
+	Cells []DeltaCell
+}
+
+// DeltaCell is a changed cell. Colours are "#rrggbb", or empty where the
+// window's background shows through.
+type DeltaCell struct {
+	Col    int
+	Row    int
+	Glyph  string
+	FColor string `json:",omitempty"`
+	BColor string `json:",omitempty"`
+}
+
+func hexColor(color sdl.Color) string {
+	if color == gterm.NoColor {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
+}
+
+// NewDelta describes how frame differs from previous, which may be nil
+func NewDelta(frame *gterm.Frame, previous *gterm.Frame) Delta {
+	delta := Delta{
+		Columns:    frame.Columns,
+		Rows:       frame.Rows,
+		Background: hexColor(frame.Background),
+		Full:       previous == nil || previous.Columns != frame.Columns || previous.Rows != frame.Rows,
+		Cells:      []DeltaCell{},
+	}
+	for _, change := range frame.Changes(previous) {
+		delta.Cells = append(delta.Cells, DeltaCell{
+			Col:    change.Col,
+			Row:    change.Row,
+			Glyph:  string(change.Glyph),
+			FColor: hexColor(change.FColor),
+			BColor: hexColor(change.BColor),
+		})
+	}
+	return delta
+}
+
+// Broadcaster streams a window's frames to any number of spectators. Attach it
+// with window.AddFrameObserver.
+type Broadcaster struct {
+	mu         sync.Mutex
+	latest     *gterm.Frame
+	spectators map[*spectator]bool
+	listeners  []net.Listener
+	closed     bool
+}
+
+type spectator struct {
+	wake chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func (s *spectator) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// NewBroadcaster constructs a broadcaster with no spectators
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{spectators: make(map[*spectator]bool)}
+}
+
+// ObserveFrame keeps frame as the latest and wakes the spectators, it never blocks
+func (broadcaster *Broadcaster) ObserveFrame(frame *gterm.Frame) {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+
+	broadcaster.latest = frame
+	for s := range broadcaster.spectators {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+			// Already woken, it will pick up this frame
+		}
+	}
+}
+
+// Spectators is how many spectators are watching
+func (broadcaster *Broadcaster) Spectators() int {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+	return len(broadcaster.spectators)
+}
+
+// Watch writes frames to w until a write fails or the broadcaster is closed.
+// The latest frame is written straight away if there is one.
+func (broadcaster *Broadcaster) Watch(w io.Writer, format Format) error {
+	return broadcaster.watch(w, format, nil)
+}
+
+// watch streams until hangup is closed as well
+func (broadcaster *Broadcaster) watch(w io.Writer, format Format, hangup <-chan struct{}) error {
+	s := &spectator{wake: make(chan struct{}, 1), done: make(chan struct{})}
+
+	broadcaster.mu.Lock()
+	if broadcaster.closed {
+		broadcaster.mu.Unlock()
+		return nil
+	}
+	broadcaster.spectators[s] = true
+	if broadcaster.latest != nil {
+		s.wake <- struct{}{}
+	}
+	broadcaster.mu.Unlock()
+
+	defer func() {
+		broadcaster.mu.Lock()
+		delete(broadcaster.spectators, s)
+		broadcaster.mu.Unlock()
+	}()
+
+	return broadcaster.stream(s, w, format, hangup)
+}
+
+func (broadcaster *Broadcaster) stream(s *spectator, w io.Writer, format Format, hangup <-chan struct{}) error {
+	var screen *telnet.Screen
+	var previous *gterm.Frame
+	encoder := json.NewEncoder(w)
+
+	for {
+		select {
+		case <-s.wake:
+		case <-s.done:
+			if format == ANSI {
+				io.WriteString(w, telnet.Reset)
+			}
+			return nil
+		case <-hangup:
+			return nil
+		}
+
+		broadcaster.mu.Lock()
+		frame := broadcaster.latest
+		broadcaster.mu.Unlock()
+
+		switch format {
+		case ANSI:
+			if screen == nil || screen.Columns != frame.Columns || screen.Rows != frame.Rows {
+				screen = telnet.NewScreen(frame.Columns, frame.Rows)
+			}
+			if err := screen.Render(w, frame); err != nil {
+				return err
+			}
+		case JSON:
+			if err := encoder.Encode(NewDelta(frame, previous)); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("Unknown spectator format %v", format)
+		}
+		previous = frame
+	}
+}
+
+// Serve lets spectators connect on listener until Close, which makes it return nil
+func (broadcaster *Broadcaster) Serve(listener net.Listener, format Format) error {
+	broadcaster.mu.Lock()
+	if broadcaster.closed {
+		broadcaster.mu.Unlock()
+		listener.Close()
+		return nil
+	}
+	broadcaster.listeners = append(broadcaster.listeners, listener)
+	broadcaster.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			broadcaster.mu.Lock()
+			closed := broadcaster.closed
+			broadcaster.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go broadcaster.watchConn(conn, format)
+	}
+}
+
+// ListenAndServe listens on addr and lets spectators connect until Close
+func (broadcaster *Broadcaster) ListenAndServe(addr string, format Format) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return broadcaster.Serve(listener, format)
+}
+
+// watchConn streams to a connection. Spectators can't send anything, their
+// input is thrown away until they hang up.
+func (broadcaster *Broadcaster) watchConn(conn net.Conn, format Format) {
+	defer conn.Close()
+
+	hangup := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, conn)
+		close(hangup)
+	}()
+	broadcaster.watch(conn, format, hangup)
+}
+
+// Close disconnects every spectator and stops serving
+func (broadcaster *Broadcaster) Close() error {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+
+	broadcaster.closed = true
+	for s := range broadcaster.spectators {
+		s.stop()
+	}
+	var err error
+	for _, listener := range broadcaster.listeners {
+		if closeErr := listener.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package spectate
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func testFrame(columns int, rows int, glyphs string) *gterm.Frame {
+	frame := &gterm.Frame{Columns: columns, Rows: rows, Background: sdl.Color{A: 255}}
+	for _, glyph := range glyphs {
+		frame.Cells = append(frame.Cells, gterm.FrameCell{Glyph: glyph, FColor: sdl.Color{R: 255, G: 128, A: 255}, BColor: gterm.NoColor})
+	}
+	return frame
+}
+
+func TestNewDelta(t *testing.T) {
+	full := NewDelta(testFrame(2, 1, "ab"), nil)
+	if !full.Full || len(full.Cells) != 2 || full.Background != "#000000" {
+		t.Errorf("Expected a full delta, got %+v", full)
+	}
+
+	delta := NewDelta(testFrame(2, 1, "aZ"), testFrame(2, 1, "ab"))
+	want := DeltaCell{Col: 1, Row: 0, Glyph: "Z", FColor: "#ff8000"}
+	if delta.Full || len(delta.Cells) != 1 || delta.Cells[0] != want {
+		t.Errorf("Expected only the changed cell %+v, got %+v", want, delta)
+	}
+}
+
+func TestWatchJSON(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	broadcaster.ObserveFrame(testFrame(2, 1, "ab"))
+
+	reader, writer := io.Pipe()
+	go broadcaster.Watch(writer, JSON)
+	lines := bufio.NewScanner(reader)
+
+	var first Delta
+	if !lines.Scan() {
+		t.Fatal("Expected the latest frame when starting to watch")
+	}
+	if err := json.Unmarshal(lines.Bytes(), &first); err != nil {
+		t.Fatal(err)
+	}
+	if !first.Full || len(first.Cells) != 2 {
+		t.Errorf("Expected a full first delta, got %+v", first)
+	}
+
+	broadcaster.ObserveFrame(testFrame(2, 1, "ax"))
+	var second Delta
+	if !lines.Scan() {
+		t.Fatal("Expected a delta for the next frame")
+	}
+	if err := json.Unmarshal(lines.Bytes(), &second); err != nil {
+		t.Fatal(err)
+	}
+	if second.Full || len(second.Cells) != 1 || second.Cells[0].Glyph != "x" {
+		t.Errorf("Expected just the changed cell, got %+v", second)
+	}
+
+	broadcaster.Close()
+	reader.Close()
+}
+
+func TestServeANSI(t *testing.T) {
+	broadcaster := NewBroadcaster()
+	defer broadcaster.Close()
+	broadcaster.ObserveFrame(testFrame(5, 1, "hello"))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go broadcaster.Serve(listener, ANSI)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var seen strings.Builder
+	buffer := make([]byte, 1024)
+	for !strings.Contains(seen.String(), "hello") {
+		n, err := conn.Read(buffer)
+		seen.Write(buffer[:n])
+		if err != nil {
+			t.Fatalf("Never saw the frame, got %q: %v", seen.String(), err)
+		}
+	}
+}
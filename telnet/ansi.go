@@ -0,0 +1,125 @@
+package telnet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type screenCell struct {
+	glyph  rune
+	fColor sdl.Color
+	bColor sdl.Color
+}
+
+// Screen draws a window to an ANSI terminal. It remembers what the terminal
+// shows, so after the first frame only cells that changed are sent.
+type Screen struct {
+	// Columns and Rows are the terminal size, cells beyond it aren't drawn
+	Columns int
+	Rows    int
+
+	drawn []screenCell
+	valid bool
+
+	// The terminal's cursor position and colours while writing a frame
+	cursorCol int
+	cursorRow int
+	fColor    sdl.Color
+	bColor    sdl.Color
+}
+
+// NewScreen constructs a screen for a terminal of columns x rows
+func NewScreen(columns int, rows int) *Screen {
+	return &Screen{Columns: columns, Rows: rows}
+}
+
+// Resize records a new terminal size, the next frame is drawn in full
+func (screen *Screen) Resize(columns int, rows int) {
+	screen.Columns = columns
+	screen.Rows = rows
+	screen.Invalidate()
+}
+
+// Invalidate forgets what the terminal shows so the next frame is drawn in full
+func (screen *Screen) Invalidate() {
+	screen.valid = false
+}
+
+// Render writes whatever changed in frame since the last one to w
+func (screen *Screen) Render(w io.Writer, frame *gterm.Frame) error {
+	var out bytes.Buffer
+
+	if !screen.valid || len(screen.drawn) != screen.Columns*screen.Rows {
+		screen.drawn = make([]screenCell, screen.Columns*screen.Rows)
+		out.WriteString("\x1b[0m\x1b[?25l\x1b[2J")
+		screen.cursorCol, screen.cursorRow = -1, -1
+		screen.fColor, screen.bColor = gterm.NoColor, gterm.NoColor
+		for i := range screen.drawn {
+			screen.drawn[i] = screenCell{glyph: ' '}
+		}
+		screen.valid = true
+	}
+
+	for row := 0; row < screen.Rows; row++ {
+		for col := 0; col < screen.Columns; col++ {
+			cell := frame.CellAt(col, row)
+			current := screenCell{glyph: cell.Glyph, fColor: cell.FColor, bColor: cell.BColor}
+			if current.bColor == gterm.NoColor {
+				current.bColor = frame.Background
+			}
+			if !unicode.IsPrint(current.glyph) {
+				current.glyph = ' '
+			}
+
+			index := col + row*screen.Columns
+			if screen.drawn[index] == current {
+				continue
+			}
+			screen.drawn[index] = current
+			screen.writeCell(&out, col, row, current)
+		}
+	}
+	if out.Len() == 0 {
+		return nil
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func (screen *Screen) writeCell(out *bytes.Buffer, col int, row int, cell screenCell) {
+	if col != screen.cursorCol || row != screen.cursorRow {
+		fmt.Fprintf(out, "\x1b[%d;%dH", row+1, col+1)
+	}
+	if cell.fColor != screen.fColor {
+		writeColor(out, 38, cell.fColor)
+		screen.fColor = cell.fColor
+	}
+	if cell.bColor != screen.bColor {
+		writeColor(out, 48, cell.bColor)
+		screen.bColor = cell.bColor
+	}
+	out.WriteRune(cell.glyph)
+	screen.cursorCol, screen.cursorRow = col+1, row
+	if screen.cursorCol >= screen.Columns {
+		// Terminals differ on where the cursor goes after the last column
+		screen.cursorCol, screen.cursorRow = -1, -1
+	}
+}
+
+// writeColor sets a 24 bit foreground (38) or background (48) colour, NoColor
+// goes back to the terminal's default
+func writeColor(out *bytes.Buffer, layer int, color sdl.Color) {
+	if color == gterm.NoColor {
+		fmt.Fprintf(out, "\x1b[%dm", layer+1)
+		return
+	}
+	fmt.Fprintf(out, "\x1b[%d;2;%d;%d;%dm", layer, color.R, color.G, color.B)
+}
+
+// Reset puts the terminal's colours and cursor back the way a shell expects
+const Reset = "\x1b[0m\x1b[?25h\x1b[2J\x1b[H"
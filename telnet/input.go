@@ -0,0 +1,318 @@
+package telnet
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Telnet commands and options, RFC 854, 857, 858 and 1073
+const (
+	iac  = 255
+	dont = 254
+	do   = 253
+	wont = 252
+	will = 251
+	sb   = 250
+	se   = 240
+
+	optionEcho              = 1
+	optionSuppressGoAhead   = 3
+	optionNegotiateWinSize  = 31
+	escape                  = 0x1b
+	maxSubnegotiationLength = 64
+	maxCSILength            = 32
+)
+
+// negotiation asks the client for character at a time input without local
+// echo, and for its window size
+var negotiation = []byte{
+	iac, will, optionEcho,
+	iac, will, optionSuppressGoAhead,
+	iac, do, optionSuppressGoAhead,
+	iac, do, optionNegotiateWinSize,
+}
+
+type decoderState int
+
+const (
+	stateData decoderState = iota
+	stateCR
+	stateIAC
+	stateOption
+	stateSubnegotiation
+	stateSubnegotiationIAC
+	stateEscape
+	stateCSI
+	stateSS3
+)
+
+// Input is what a chunk of the stream decoded to
+type Input struct {
+	// Events are key presses as SDL events, so apps handle them like desktop input
+	Events []sdl.Event
+	// Resized is set when the client reported its window size
+	Resized bool
+	Columns int
+	Rows    int
+	// Reply is telnet negotiation to send back to the client
+	Reply []byte
+}
+
+// Decoder splits a telnet stream into key presses and window size reports
+type Decoder struct {
+	state   decoderState
+	command byte
+	sub     []byte
+	params  []byte
+	// accepted are the options we asked for, replies about them are not answered again
+	accepted map[byte]bool
+}
+
+// NewDecoder constructs a decoder for a session that sent the standard negotiation
+func NewDecoder() *Decoder {
+	return &Decoder{accepted: map[byte]bool{
+		optionEcho:             true,
+		optionSuppressGoAhead:  true,
+		optionNegotiateWinSize: true,
+	}}
+}
+
+// shiftedKeys maps characters typed with shift on a US keyboard to the key pressed
+var shiftedKeys = map[byte]byte{
+	'~': '`', '!': '1', '@': '2', '#': '3', '$': '4', '%': '5', '^': '6', '&': '7',
+	'*': '8', '(': '9', ')': '0', '_': '-', '+': '=', '{': '[', '}': ']', '|': '\\',
+	':': ';', '"': '\'', '<': ',', '>': '.', '?': '/',
+}
+
+func keyDown(key sdl.Keycode, mod int) sdl.Event {
+	return &sdl.KeyDownEvent{
+		Type:   sdl.KEYDOWN,
+		State:  sdl.PRESSED,
+		Keysym: sdl.Keysym{Sym: key, Mod: uint16(mod)},
+	}
+}
+
+func textInput(b byte) sdl.Event {
+	event := &sdl.TextInputEvent{Type: sdl.TEXTINPUT}
+	event.Text[0] = b
+	return event
+}
+
+// keyEvents turns a typed character into the key press that makes it
+func keyEvents(b byte, mod int) []sdl.Event {
+	switch {
+	case b == '\r' || b == '\n':
+		return []sdl.Event{keyDown(sdl.K_RETURN, mod)}
+	case b == '\t':
+		return []sdl.Event{keyDown(sdl.K_TAB, mod)}
+	case b == 0x7f || b == 0x08:
+		return []sdl.Event{keyDown(sdl.K_BACKSPACE, mod)}
+	case b >= 1 && b <= 26:
+		return []sdl.Event{keyDown(sdl.Keycode('a'+b-1), mod|sdl.KMOD_LCTRL)}
+	case b >= 'A' && b <= 'Z':
+		events := []sdl.Event{keyDown(sdl.Keycode(b-'A'+'a'), mod|sdl.KMOD_LSHIFT)}
+		if mod == 0 {
+			events = append(events, textInput(b))
+		}
+		return events
+	case b >= ' ' && b < 0x7f:
+		key, shift := b, 0
+		if unshifted, ok := shiftedKeys[b]; ok {
+			key, shift = unshifted, sdl.KMOD_LSHIFT
+		}
+		events := []sdl.Event{keyDown(sdl.Keycode(key), mod|shift)}
+		if mod == 0 {
+			events = append(events, textInput(b))
+		}
+		return events
+	}
+	return nil
+}
+
+// xtermModifiers decodes the modifier parameter of an xterm key sequence
+func xtermModifiers(param int) int {
+	if param < 2 {
+		return 0
+	}
+	bits := param - 1
+	mod := 0
+	if bits&1 != 0 {
+		mod |= sdl.KMOD_LSHIFT
+	}
+	if bits&2 != 0 {
+		mod |= sdl.KMOD_LALT
+	}
+	if bits&4 != 0 {
+		mod |= sdl.KMOD_LCTRL
+	}
+	return mod
+}
+
+// tildeKeys are the keys sent as ESC [ n ~
+var tildeKeys = map[int]sdl.Keycode{
+	1: sdl.K_HOME, 2: sdl.K_INSERT, 3: sdl.K_DELETE, 4: sdl.K_END, 5: sdl.K_PAGEUP, 6: sdl.K_PAGEDOWN,
+	7: sdl.K_HOME, 8: sdl.K_END,
+	11: sdl.K_F1, 12: sdl.K_F2, 13: sdl.K_F3, 14: sdl.K_F4, 15: sdl.K_F5,
+	17: sdl.K_F6, 18: sdl.K_F7, 19: sdl.K_F8, 20: sdl.K_F9, 21: sdl.K_F10, 23: sdl.K_F11, 24: sdl.K_F12,
+}
+
+// letterKeys are the keys sent as ESC [ x or ESC O x
+var letterKeys = map[byte]sdl.Keycode{
+	'A': sdl.K_UP, 'B': sdl.K_DOWN, 'C': sdl.K_RIGHT, 'D': sdl.K_LEFT,
+	'H': sdl.K_HOME, 'F': sdl.K_END,
+	'P': sdl.K_F1, 'Q': sdl.K_F2, 'R': sdl.K_F3, 'S': sdl.K_F4,
+}
+
+// parseParams splits "1;5" into numbers, missing numbers are zero
+func parseParams(params []byte) []int {
+	numbers := []int{0}
+	for _, b := range params {
+		if b == ';' {
+			numbers = append(numbers, 0)
+		} else if b >= '0' && b <= '9' {
+			numbers[len(numbers)-1] = numbers[len(numbers)-1]*10 + int(b-'0')
+		}
+	}
+	return numbers
+}
+
+func (decoder *Decoder) finishCSI(final byte) []sdl.Event {
+	params := parseParams(decoder.params)
+	mod := 0
+	if len(params) > 1 {
+		mod = xtermModifiers(params[1])
+	}
+	if final == '~' {
+		if key, ok := tildeKeys[params[0]]; ok {
+			return []sdl.Event{keyDown(key, mod)}
+		}
+		return nil
+	}
+	if key, ok := letterKeys[final]; ok {
+		return []sdl.Event{keyDown(key, mod)}
+	}
+	return nil
+}
+
+func (decoder *Decoder) subnegotiation(input *Input) {
+	sub := decoder.sub
+	if len(sub) == 5 && sub[0] == optionNegotiateWinSize {
+		input.Resized = true
+		input.Columns = int(sub[1])<<8 | int(sub[2])
+		input.Rows = int(sub[3])<<8 | int(sub[4])
+	}
+}
+
+// negotiate answers the client's WILL, WONT, DO and DONT. Options we asked for
+// are already agreed, anything else is refused.
+func (decoder *Decoder) negotiate(command byte, option byte, input *Input) {
+	if decoder.accepted[option] {
+		return
+	}
+	switch command {
+	case will:
+		input.Reply = append(input.Reply, iac, dont, option)
+	case do:
+		input.Reply = append(input.Reply, iac, wont, option)
+	}
+	// WONT and DONT need no answer
+}
+
+// Decode feeds a chunk of the stream through the decoder. An escape at the
+// very end of a chunk is taken to be the escape key, terminals send the rest
+// of a key sequence in the same write.
+func (decoder *Decoder) Decode(data []byte) Input {
+	var input Input
+	for _, b := range data {
+		switch decoder.state {
+		case stateCR:
+			decoder.state = stateData
+			if b == '\n' || b == 0 {
+				continue
+			}
+			fallthrough
+		case stateData:
+			switch {
+			case b == iac:
+				decoder.state = stateIAC
+			case b == escape:
+				decoder.state = stateEscape
+			case b == '\r':
+				input.Events = append(input.Events, keyEvents(b, 0)...)
+				decoder.state = stateCR
+			default:
+				input.Events = append(input.Events, keyEvents(b, 0)...)
+			}
+		case stateIAC:
+			switch b {
+			case iac:
+				// An escaped 255 isn't a key we understand
+				decoder.state = stateData
+			case will, wont, do, dont:
+				decoder.command = b
+				decoder.state = stateOption
+			case sb:
+				decoder.sub = decoder.sub[:0]
+				decoder.state = stateSubnegotiation
+			default:
+				decoder.state = stateData
+			}
+		case stateOption:
+			decoder.negotiate(decoder.command, b, &input)
+			decoder.state = stateData
+		case stateSubnegotiation:
+			if b == iac {
+				decoder.state = stateSubnegotiationIAC
+			} else if len(decoder.sub) < maxSubnegotiationLength {
+				decoder.sub = append(decoder.sub, b)
+			}
+		case stateSubnegotiationIAC:
+			switch b {
+			case se:
+				decoder.subnegotiation(&input)
+				decoder.state = stateData
+			case iac:
+				decoder.sub = append(decoder.sub, iac)
+				decoder.state = stateSubnegotiation
+			default:
+				decoder.state = stateSubnegotiation
+			}
+		case stateEscape:
+			switch b {
+			case '[':
+				decoder.params = decoder.params[:0]
+				decoder.state = stateCSI
+			case 'O':
+				decoder.state = stateSS3
+			case escape:
+				input.Events = append(input.Events, keyDown(sdl.K_ESCAPE, 0))
+			default:
+				// Escape before a character is how terminals send alt
+				input.Events = append(input.Events, keyEvents(b, sdl.KMOD_LALT)...)
+				decoder.state = stateData
+			}
+		case stateCSI:
+			if (b >= '0' && b <= '9') || b == ';' {
+				if len(decoder.params) >= maxCSILength {
+					// No key sends parameters this long, give up on the sequence
+					decoder.state = stateData
+					continue
+				}
+				decoder.params = append(decoder.params, b)
+				continue
+			}
+			input.Events = append(input.Events, decoder.finishCSI(b)...)
+			decoder.state = stateData
+		case stateSS3:
+			if key, ok := letterKeys[b]; ok {
+				input.Events = append(input.Events, keyDown(key, 0))
+			}
+			decoder.state = stateData
+		}
+	}
+
+	if decoder.state == stateEscape {
+		input.Events = append(input.Events, keyDown(sdl.K_ESCAPE, 0))
+		decoder.state = stateData
+	}
+	return input
+}
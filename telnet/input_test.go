@@ -0,0 +1,106 @@
+package telnet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// keys lists the key presses in events, skipping text input
+func keys(events []sdl.Event) []sdl.Keysym {
+	var syms []sdl.Keysym
+	for _, event := range events {
+		if e, ok := event.(*sdl.KeyDownEvent); ok {
+			syms = append(syms, sdl.Keysym{Sym: e.Keysym.Sym, Mod: e.Keysym.Mod})
+		}
+	}
+	return syms
+}
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []sdl.Keysym
+	}{
+		{"letter", "x", []sdl.Keysym{{Sym: sdl.Keycode('x')}}},
+		{"capital", "X", []sdl.Keysym{{Sym: sdl.Keycode('x'), Mod: sdl.KMOD_LSHIFT}}},
+		{"shifted symbol", "?", []sdl.Keysym{{Sym: sdl.Keycode('/'), Mod: sdl.KMOD_LSHIFT}}},
+		{"control", "\x03", []sdl.Keysym{{Sym: sdl.Keycode('c'), Mod: sdl.KMOD_LCTRL}}},
+		{"enter", "\r\n", []sdl.Keysym{{Sym: sdl.K_RETURN}}},
+		{"enter with nul", "\r\x00", []sdl.Keysym{{Sym: sdl.K_RETURN}}},
+		{"arrows", "\x1b[A\x1bOD", []sdl.Keysym{{Sym: sdl.K_UP}, {Sym: sdl.K_LEFT}}},
+		{"shift arrow", "\x1b[1;2C", []sdl.Keysym{{Sym: sdl.K_RIGHT, Mod: sdl.KMOD_LSHIFT}}},
+		{"ctrl arrow", "\x1b[1;5B", []sdl.Keysym{{Sym: sdl.K_DOWN, Mod: sdl.KMOD_LCTRL}}},
+		{"page down", "\x1b[6~", []sdl.Keysym{{Sym: sdl.K_PAGEDOWN}}},
+		{"alt", "\x1bq", []sdl.Keysym{{Sym: sdl.Keycode('q'), Mod: sdl.KMOD_LALT}}},
+		{"lone escape", "\x1b", []sdl.Keysym{{Sym: sdl.K_ESCAPE}}},
+	}
+
+	for _, test := range tests {
+		got := keys(NewDecoder().Decode([]byte(test.input)).Events)
+		if len(got) != len(test.want) {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v: key %v is %v, want %v", test.name, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestDecodeTextInput(t *testing.T) {
+	events := NewDecoder().Decode([]byte("a")).Events
+	if len(events) != 2 {
+		t.Fatalf("Expected a key press and text input, got %v events", len(events))
+	}
+	text, ok := events[1].(*sdl.TextInputEvent)
+	if !ok || text.Text[0] != 'a' {
+		t.Errorf("Expected text input of 'a', got %#v", events[1])
+	}
+}
+
+func TestDecodeSplitSequence(t *testing.T) {
+	decoder := NewDecoder()
+	first := decoder.Decode([]byte("\x1b["))
+	second := decoder.Decode([]byte("A"))
+	if len(first.Events) != 0 {
+		t.Errorf("Expected nothing from half a sequence, got %v", keys(first.Events))
+	}
+	if got := keys(second.Events); len(got) != 1 || got[0].Sym != sdl.K_UP {
+		t.Errorf("Expected up from the rest of the sequence, got %v", got)
+	}
+}
+
+func TestDecodeEndlessSequence(t *testing.T) {
+	decoder := NewDecoder()
+	decoder.Decode([]byte("\x1b[" + strings.Repeat("1;", 1000)))
+	if len(decoder.params) > maxCSILength || decoder.state != stateData {
+		t.Errorf("Expected the sequence abandoned at %v bytes, kept %v", maxCSILength, len(decoder.params))
+	}
+}
+
+func TestDecodeWindowSize(t *testing.T) {
+	input := NewDecoder().Decode([]byte{iac, sb, optionNegotiateWinSize, 0, 120, 0, 40, iac, se, 'x'})
+	if !input.Resized || input.Columns != 120 || input.Rows != 40 {
+		t.Errorf("Expected a resize to 120x40, got %v %vx%v", input.Resized, input.Columns, input.Rows)
+	}
+	if got := keys(input.Events); len(got) != 1 || got[0].Sym != sdl.K_x {
+		t.Errorf("Expected the key after the size report, got %v", got)
+	}
+}
+
+func TestDecodeNegotiation(t *testing.T) {
+	input := NewDecoder().Decode([]byte{iac, will, optionNegotiateWinSize, iac, will, 24, iac, do, 5})
+	want := []byte{iac, dont, 24, iac, wont, 5}
+	if !bytes.Equal(input.Reply, want) {
+		t.Errorf("Expected only unknown options refused, got %v want %v", input.Reply, want)
+	}
+	if len(input.Events) != 0 {
+		t.Errorf("Expected no key presses from negotiation, got %v", keys(input.Events))
+	}
+}
@@ -0,0 +1,346 @@
+// Package telnet serves gterm apps to terminals over TCP. Each connection gets
+// its own virtual window and app, drawn to the socket as ANSI and driven by
+// key presses decoded from the stream.
+package telnet
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Terminals reporting a bigger size than this are taken to be this big, so a
+// client can't have its session allocate cells without limit
+const (
+	maxColumns = 500
+	maxRows    = 200
+)
+
+// Server accepts connections and runs an app for each one
+type Server struct {
+	// NewApp starts the app for a new session, drawing into session.Window
+	NewApp func(session *Session) gterm.App
+
+	// Columns and Rows size each session's window
+	Columns int
+	Rows    int
+	// Resizable windows follow the terminal's size, otherwise terminals
+	// smaller than the window are asked to grow
+	Resizable bool
+
+	// IdleTimeout disconnects sessions that haven't sent anything for this
+	// long, zero never does
+	IdleTimeout time.Duration
+	// Step is the frame length while a session is animating. Defaults to 50ms,
+	// terminals can't keep up with much faster.
+	Step time.Duration
+
+	mu       sync.Mutex
+	listener net.Listener
+	sessions map[*Session]bool
+	closed   bool
+}
+
+// ListenAndServe listens on addr and serves sessions until Close
+func (server *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// Serve accepts connections on listener until Close, which makes it return nil
+func (server *Server) Serve(listener net.Listener) error {
+	if server.NewApp == nil {
+		return errors.New("Server needs NewApp to start sessions")
+	}
+
+	server.mu.Lock()
+	if server.closed {
+		server.mu.Unlock()
+		listener.Close()
+		return nil
+	}
+	server.listener = listener
+	if server.sessions == nil {
+		server.sessions = make(map[*Session]bool)
+	}
+	server.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			server.mu.Lock()
+			closed := server.closed
+			server.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		session := server.newSession(conn)
+		server.mu.Lock()
+		server.sessions[session] = true
+		server.mu.Unlock()
+
+		go func() {
+			session.run()
+			server.mu.Lock()
+			delete(server.sessions, session)
+			server.mu.Unlock()
+		}()
+	}
+}
+
+// Close stops accepting connections and disconnects every session
+func (server *Server) Close() error {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.closed = true
+	for session := range server.sessions {
+		session.Close()
+	}
+	if server.listener != nil {
+		return server.listener.Close()
+	}
+	return nil
+}
+
+// Sessions is how many sessions are connected
+func (server *Server) Sessions() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return len(server.sessions)
+}
+
+// Session is a single connected terminal
+type Session struct {
+	// Window is the session's own virtual window
+	Window *gterm.Window
+	// Columns and Rows are the terminal's size, as last reported, or the
+	// server's until it reports one
+	Columns int
+	Rows    int
+
+	server  *Server
+	conn    net.Conn
+	screen  *Screen
+	decoder *Decoder
+	events  chan sdl.Event
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (server *Server) newSession(conn net.Conn) *Session {
+	columns, rows := server.Columns, server.Rows
+	if columns <= 0 || rows <= 0 {
+		columns, rows = 80, 24
+	}
+	// Terminals that never report their size are taken to fit the window
+	return &Session{
+		Window:  gterm.NewVirtualWindow(columns, rows),
+		Columns: columns,
+		Rows:    rows,
+		server:  server,
+		conn:    conn,
+		screen:  NewScreen(columns, rows),
+		decoder: NewDecoder(),
+		events:  make(chan sdl.Event, 64),
+		done:    make(chan struct{}),
+	}
+}
+
+// RemoteAddr is the address the session connected from
+func (session *Session) RemoteAddr() net.Addr {
+	return session.conn.RemoteAddr()
+}
+
+// Close disconnects the session, its app sees a quit event
+func (session *Session) Close() {
+	session.closeOnce.Do(func() {
+		close(session.done)
+		session.conn.Close()
+	})
+}
+
+func (session *Session) write(data []byte) error {
+	session.writeMu.Lock()
+	defer session.writeMu.Unlock()
+	_, err := session.conn.Write(data)
+	return err
+}
+
+// read decodes input until the connection closes or goes idle
+func (session *Session) read() {
+	defer close(session.events)
+
+	buffer := make([]byte, 512)
+	for {
+		if session.server.IdleTimeout > 0 {
+			session.conn.SetReadDeadline(time.Now().Add(session.server.IdleTimeout))
+		}
+		n, err := session.conn.Read(buffer)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				session.write([]byte(Reset + "Disconnected after being idle too long.\r\n"))
+			}
+			return
+		}
+
+		input := session.decoder.Decode(buffer[:n])
+		if len(input.Reply) > 0 {
+			if err := session.write(input.Reply); err != nil {
+				return
+			}
+		}
+		if input.Resized {
+			columns, rows := input.Columns, input.Rows
+			if columns > maxColumns {
+				columns = maxColumns
+			}
+			if rows > maxRows {
+				rows = maxRows
+			}
+			resize := &sdl.WindowEvent{Type: sdl.WINDOWEVENT, Event: sdl.WINDOWEVENT_RESIZED, Data1: int32(columns), Data2: int32(rows)}
+			if !session.send(resize) {
+				return
+			}
+		}
+		for _, event := range input.Events {
+			if !session.send(event) {
+				return
+			}
+		}
+	}
+}
+
+func (session *Session) send(event sdl.Event) bool {
+	select {
+	case session.events <- event:
+		return true
+	case <-session.done:
+		return false
+	}
+}
+
+func (session *Session) resize(columns int, rows int) {
+	if columns <= 0 || rows <= 0 {
+		return
+	}
+	session.Columns, session.Rows = columns, rows
+	session.screen.Resize(columns, rows)
+	if session.server.Resizable {
+		session.Window.Resize(columns, rows)
+	}
+}
+
+func (session *Session) animating(app gterm.App) bool {
+	return app.Animating() || session.Window.Animating() || session.Window.Effecting()
+}
+
+// render draws the app, or asks for a bigger terminal when the window doesn't fit
+func (session *Session) render(app gterm.App) error {
+	window := session.Window
+	if window.Columns > session.Columns || window.Rows > session.Rows {
+		message := fmt.Sprintf("Please make your terminal at least %vx%v", window.Columns, window.Rows)
+		small := gterm.NewVirtualWindow(session.Columns, session.Rows)
+		small.PutString(0, 0, message, sdl.Color{R: 255, G: 255, B: 255, A: 255})
+		return session.screen.Render(session, small.Frame())
+	}
+
+	app.Render(window)
+	window.Advance()
+	return session.screen.Render(session, window.Frame())
+}
+
+// Write sends a frame to the terminal, it lets the session stand in for the connection
+func (session *Session) Write(data []byte) (int, error) {
+	if err := session.write(data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// run plays the session's app until it is done or the terminal disconnects
+func (session *Session) run() {
+	defer session.Close()
+
+	if err := session.write(negotiation); err != nil {
+		return
+	}
+	go session.read()
+
+	step := session.server.Step
+	if step <= 0 {
+		step = 50 * time.Millisecond
+	}
+
+	app := session.server.NewApp(session)
+	last := time.Now()
+	app.Update(0)
+	if err := session.render(app); err != nil {
+		return
+	}
+
+	for !app.Done() {
+		var timeout <-chan time.Time
+		if session.animating(app) {
+			timeout = time.After(step)
+		}
+
+		select {
+		case event, ok := <-session.events:
+			if !ok {
+				// Gone, give the app a chance to tidy up
+				app.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+				return
+			}
+			session.handle(app, event)
+		drain:
+			for {
+				select {
+				case event, ok := <-session.events:
+					if !ok {
+						break drain
+					}
+					session.handle(app, event)
+				default:
+					break drain
+				}
+			}
+		case <-timeout:
+		case <-session.done:
+			app.HandleEvent(&sdl.QuitEvent{Type: sdl.QUIT})
+			return
+		}
+
+		now := time.Now()
+		app.Update(uint32(now.Sub(last) / time.Millisecond))
+		last = now
+
+		if err := session.render(app); err != nil {
+			log.Println("Failed to draw to", session.RemoteAddr(), err)
+			return
+		}
+	}
+
+	session.write([]byte(Reset))
+}
+
+func (session *Session) handle(app gterm.App, event sdl.Event) {
+	if e, ok := event.(*sdl.WindowEvent); ok && e.Event == sdl.WINDOWEVENT_RESIZED {
+		session.resize(int(e.Data1), int(e.Data2))
+	}
+	app.HandleEvent(event)
+}
@@ -0,0 +1,181 @@
+package telnet
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomas-holmes/gterm"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// echoApp shows the last key typed and finishes on q
+type echoApp struct {
+	last rune
+	done bool
+}
+
+func (app *echoApp) HandleEvent(event sdl.Event) {
+	if e, ok := event.(*sdl.KeyDownEvent); ok {
+		app.last = rune(e.Keysym.Sym)
+		app.done = app.last == 'q'
+	}
+}
+
+func (app *echoApp) Update(delta uint32) {}
+
+func (app *echoApp) Render(window *gterm.Window) {
+	window.ClearWindow()
+	if app.last != 0 {
+		window.PutString(0, 0, "key "+string(app.last), sdl.Color{R: 255, G: 255, B: 255, A: 255})
+	}
+}
+
+func (app *echoApp) Animating() bool { return false }
+func (app *echoApp) Done() bool      { return app.done }
+
+func startServer(t *testing.T, server *Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(listener)
+	return listener.Addr().String()
+}
+
+// readUntil reads from conn until it has seen want
+func readUntil(t *testing.T, conn net.Conn, want string) string {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var seen bytes.Buffer
+	buffer := make([]byte, 1024)
+	for !strings.Contains(seen.String(), want) {
+		n, err := conn.Read(buffer)
+		seen.Write(buffer[:n])
+		if err != nil {
+			t.Fatalf("Never saw %q, got %q: %v", want, seen.String(), err)
+		}
+	}
+	return seen.String()
+}
+
+func TestServerSession(t *testing.T) {
+	server := &Server{
+		Columns: 20,
+		Rows:    5,
+		NewApp:  func(session *Session) gterm.App { return &echoApp{} },
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	readUntil(t, conn, string(negotiation))
+	conn.Write([]byte{iac, sb, optionNegotiateWinSize, 0, 40, 0, 10, iac, se})
+	conn.Write([]byte("x"))
+	readUntil(t, conn, "x")
+
+	conn.Write([]byte("q"))
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	rest, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(rest), Reset) {
+		t.Errorf("Expected the terminal reset when the app finished, got %q", rest)
+	}
+}
+
+func TestServerTooSmall(t *testing.T) {
+	server := &Server{
+		Columns: 100,
+		Rows:    30,
+		NewApp:  func(session *Session) gterm.App { return &echoApp{} },
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	readUntil(t, conn, string(negotiation))
+	conn.Write([]byte{iac, sb, optionNegotiateWinSize, 0, 80, 0, 24, iac, se})
+	readUntil(t, conn, "100x30")
+}
+
+func TestServerHugeWindowSize(t *testing.T) {
+	server := &Server{
+		Columns:   20,
+		Rows:      5,
+		Resizable: true,
+		NewApp:    func(session *Session) gterm.App { return &echoApp{} },
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// 65535x65535, with every 255 escaped as telnet requires
+	readUntil(t, conn, string(negotiation))
+	conn.Write([]byte{iac, sb, optionNegotiateWinSize, iac, iac, iac, iac, iac, iac, iac, iac, iac, se})
+	conn.Write([]byte("x"))
+	readUntil(t, conn, "key x")
+}
+
+func TestServerWithoutWindowSize(t *testing.T) {
+	server := &Server{
+		Columns: 100,
+		Rows:    30,
+		NewApp:  func(session *Session) gterm.App { return &echoApp{} },
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Never answering the size negotiation leaves the terminal at the window's size
+	readUntil(t, conn, string(negotiation))
+	conn.Write([]byte("x"))
+	if output := readUntil(t, conn, "key x"); strings.Contains(output, "100x30") {
+		t.Errorf("Expected the app without asking for a bigger terminal, got %q", output)
+	}
+}
+
+func TestServerIdleTimeout(t *testing.T) {
+	server := &Server{
+		Columns:     20,
+		Rows:        5,
+		IdleTimeout: 50 * time.Millisecond,
+		NewApp:      func(session *Session) gterm.App { return &echoApp{} },
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", startServer(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	output, err := ioutil.ReadAll(conn)
+	if err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "idle") {
+		t.Errorf("Expected to be told about the idle disconnect, got %q", output)
+	}
+}
@@ -17,29 +17,60 @@ var CP437 = charmap.CodePage437
 
 // Window represents the base window object
 type Window struct {
-	Columns         int
-	Rows            int
-	FontSize        int
-	FontHPixel      int
-	FontWPixel      int
-	DisplayHPixel   int
-	DisplayWPixel   int
-	HeightPixel     int
-	WidthPixel      int
-	fontPath        string
-	fontSheet       *sdl.Texture
-	spritesPerRow   int
-	SdlWindow       *sdl.Window
-	SdlRenderer     *sdl.Renderer
-	backgroundColor sdl.Color
-	cells           []cell
-	fps             fpsCounter
-	vsync           bool
+	Columns           int
+	Rows              int
+	FontSize          int
+	FontHPixel        int
+	FontWPixel        int
+	DisplayHPixel     int
+	DisplayWPixel     int
+	HeightPixel       int
+	WidthPixel        int
+	fontPath          string
+	fontSheet         *sdl.Texture
+	fonts             *FontRegistry
+	fontIndex         int
+	spritesPerRow     int
+	SdlWindow         *sdl.Window
+	SdlRenderer       *sdl.Renderer
+	backgroundColor   sdl.Color
+	cells             []cell
+	fps               fpsCounter
+	vsync             bool
+	highDPI           bool
+	geometry          Geometry
+	geometryRestored  bool
+	effects           []activeEffect
+	effectTicks       uint32
+	offsetX           int
+	offsetY           int
+	animatedCells     int
+	nextAnimationTick uint32
+	drawn             []cell
+	target            *sdl.Texture
+	redrawAll         bool
+	effectsDrawn      bool
+	floating          []*FloatingGlyph
+	floatingDrawn     []floatingState
+	floatingMoving    bool
+	events            EventSource
+	surface           *sdl.Surface
+	observers         []FrameObserver
+	title             string
+	textSink          TextSink
+	colorFilter       ColorFilter
+	atlas             *Atlas
+	hits              hitRegions
 }
 
 type cell struct {
 	bgColor     sdl.Color
 	renderItems []renderItem
+	animation   *GlyphAnimation
+	frame       int
+	light       sdl.Color
+	decorations []Decoration
+	blinkHidden uint32
 }
 
 type renderItem struct {
@@ -58,10 +89,11 @@ func NewWindow(columns int, rows int, fontPath string, fontX int, fontY int, vsy
 		fontPath:      fontPath,
 		cells:         cells,
 		vsync:         vsync,
-		FontHPixel:    fontX,
-		FontWPixel:    fontY,
-		DisplayHPixel: fontX,
-		DisplayWPixel: fontY,
+		FontWPixel:    fontX,
+		FontHPixel:    fontY,
+		DisplayWPixel: fontX,
+		DisplayHPixel: fontY,
+		fontIndex:     -1,
 
 		WidthPixel:  columns * fontX,
 		HeightPixel: rows * fontY,
@@ -70,34 +102,64 @@ func NewWindow(columns int, rows int, fontPath string, fontX int, fontY int, vsy
 }
 
 func (window *Window) SetTitle(title string) {
-	window.SdlWindow.SetTitle(title)
+	window.title = title
+	if window.SdlWindow != nil {
+		window.SdlWindow.SetTitle(title)
+	}
 }
 
 func (window *Window) ChangeFont(fontPath string, w, h int) error {
 	window.fontPath = fontPath
+	window.atlas = nil
 	newFont, err := window.loadFont(w)
 	if err != nil {
 		return err
 	}
 
+	window.useFontSheet(newFont, w, h)
+	window.syncFontIndex()
+	return nil
+}
+
+// useFontSheet swaps in a newly loaded font texture of w x h cells
+func (window *Window) useFontSheet(newFont *sdl.Texture, w int, h int) {
 	oldFont := window.fontSheet
 	oldFont.Destroy()
 
 	window.fontSheet = newFont
 	window.FontWPixel = w
 	window.FontHPixel = h
-	window.SdlWindow.SetSize(window.Columns*w, window.Rows*h)
-
-	return nil
+	window.DisplayWPixel = w
+	window.DisplayHPixel = h
+	window.WidthPixel = window.Columns * w
+	window.HeightPixel = window.Rows * h
+	if window.SdlWindow != nil {
+		window.SdlWindow.SetSize(window.WidthPixel, window.HeightPixel)
+	}
+	window.resetTarget()
 }
 
+// updateSize scales cells to the drawable size, which is larger than the window size on high-DPI displays
 func (window *Window) updateSize() {
-	actualW, actualH := window.SdlWindow.GetSize()
-	window.DisplayWPixel = actualW / window.Columns
-	window.DisplayHPixel = actualH / window.Rows
+	actualW, actualH, err := window.SdlRenderer.GetOutputSize()
+	if err != nil && window.SdlWindow != nil {
+		actualW, actualH = window.SdlWindow.GetSize()
+	} else if err != nil {
+		actualW, actualH = window.WidthPixel, window.HeightPixel
+	}
+	displayW, displayH := actualW/window.Columns, actualH/window.Rows
+	if displayW != window.DisplayWPixel || displayH != window.DisplayHPixel {
+		window.resetTarget()
+	}
+	window.DisplayWPixel = displayW
+	window.DisplayHPixel = displayH
 }
 
 func (window *Window) loadFont(w int) (*sdl.Texture, error) {
+	if window.atlas != nil {
+		return window.atlasTexture(window.atlas)
+	}
+
 	rwops := sdl.RWFromFile(window.fontPath, "rb")
 	if rwops == nil {
 		return nil, fmt.Errorf("Failed to load image from %s", window.fontPath)
@@ -134,7 +196,7 @@ func (window *Window) Init() error {
 		return errors.New("Failed to initialize sdl2_img for PNG")
 	}
 
-	sdlWindow, err := sdl.CreateWindow("", sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, window.WidthPixel, window.HeightPixel, sdl.WINDOW_SHOWN|sdl.WINDOW_RESIZABLE)
+	sdlWindow, err := window.createWindow()
 	if err != nil {
 		return err
 	}
@@ -171,6 +233,7 @@ func (window *Window) Init() error {
 
 func (window *Window) SetBackgroundColor(color sdl.Color) {
 	window.backgroundColor = color
+	window.redrawAll = true
 }
 
 func (window *Window) cellIndex(col int, row int) (int, error) {
@@ -180,46 +243,71 @@ func (window *Window) cellIndex(col int, row int) (int, error) {
 	return col + window.Columns*row, nil
 }
 
+func (window *Window) cellRect(cellCol int, cellRow int) sdl.Rect {
+	destX := cellCol*window.DisplayWPixel + window.offsetX
+	destY := cellRow*window.DisplayHPixel + window.offsetY
+	return sdl.Rect{X: int32(destX), Y: int32(destY), W: int32(window.DisplayWPixel), H: int32(window.DisplayHPixel)}
+}
+
 func (window *Window) renderCell(cellCol int, cellRow int) error {
 	idx, err := window.cellIndex(cellCol, cellRow)
 	if err != nil {
 		return err
 	}
 
-	destX := cellCol * window.DisplayWPixel
-	destY := cellRow * window.DisplayHPixel
-	destRect := sdl.Rect{X: int32(destX), Y: int32(destY), W: int32(window.DisplayWPixel), H: int32(window.DisplayHPixel)}
+	destRect := window.cellRect(cellCol, cellRow)
 
 	cell := window.cells[idx]
+	bgColor := applyLight(cell.bgColor, cell.light)
 	for _, item := range cell.renderItems {
-		runeByte, ok := CP437.EncodeRune(item.Glyph)
-		if !ok {
-			log.Println("Could not encode rune", item.Glyph)
+		item.FColor = applyLight(item.FColor, cell.light)
+		if err := window.renderGlyph(item, bgColor, destRect); err != nil {
+			return err
 		}
+	}
 
-		row := int(runeByte) / window.spritesPerRow
-		col := int(runeByte) % window.spritesPerRow
-		sX := col * window.FontWPixel
-		sY := row * window.FontHPixel
+	if err := window.renderAnimationFrame(cell, destRect); err != nil {
+		return err
+	}
 
-		sourceRect := sdl.Rect{X: int32(sX), Y: int32(sY), W: int32(window.FontWPixel), H: int32(window.FontHPixel)}
+	return window.renderDecorations(cell, destRect)
+}
 
-		if cell.bgColor != NoColor {
-			color := cell.bgColor
-			r, g, b, a := uint8(color.R), uint8(color.G), uint8(color.B), uint8(color.A)
-			window.SdlRenderer.SetDrawColor(r, g, b, a)
-			window.SdlRenderer.FillRect(&destRect)
-		}
+func (window *Window) renderGlyph(item renderItem, bgColor sdl.Color, destRect sdl.Rect) error {
+	if bgColor != NoColor {
+		color := window.transformColor(bgColor)
+		r, g, b, a := uint8(color.R), uint8(color.G), uint8(color.B), uint8(color.A)
+		window.SdlRenderer.SetDrawColor(r, g, b, a)
+		window.SdlRenderer.FillRect(&destRect)
+	}
 
-		color := item.FColor
-		r, g, b := uint8(color.R), uint8(color.G), uint8(color.B)
-		window.fontSheet.SetColorMod(r, g, b)
-		if err := window.SdlRenderer.Copy(window.fontSheet, &sourceRect, &destRect); err != nil {
-			return err
+	color := window.transformColor(item.FColor)
+	r, g, b := uint8(color.R), uint8(color.G), uint8(color.B)
+
+	index, ok := window.glyphIndex(item.Glyph)
+	if !ok {
+		// Block elements and braille missing from the sheet are drawn by hand
+		if rects, isBlock := blockRects(item.Glyph, destRect); isBlock {
+			if len(rects) > 0 {
+				window.SdlRenderer.SetDrawColor(r, g, b, color.A)
+				if err := window.SdlRenderer.FillRects(rects); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
+		log.Println("Could not encode rune", item.Glyph)
 	}
 
-	return nil
+	row := index / window.spritesPerRow
+	col := index % window.spritesPerRow
+	sX := col * window.FontWPixel
+	sY := row * window.FontHPixel
+
+	sourceRect := sdl.Rect{X: int32(sX), Y: int32(sY), W: int32(window.FontWPixel), H: int32(window.FontHPixel)}
+
+	window.fontSheet.SetColorMod(r, g, b)
+	return window.SdlRenderer.Copy(window.fontSheet, &sourceRect, &destRect)
 }
 
 func (window *Window) renderCells() error {
@@ -287,7 +375,12 @@ func (window *Window) ClearCell(col int, row int) error {
 }
 
 func (window *Window) ClearWindow() {
-	window.cells = make([]cell, window.Columns*window.Rows, window.Columns*window.Rows)
+	for i := range window.cells {
+		window.cells[i] = cell{
+			renderItems: window.cells[i].renderItems[:0],
+			decorations: window.cells[i].decorations[:0],
+		}
+	}
 }
 
 func (window *Window) ShouldRenderFps(shouldRender bool) {
@@ -330,19 +423,42 @@ func (window *Window) DebugDrawSpriteSheet() error {
 	return window.SdlRenderer.Copy(window.fontSheet, nil, &sdl.Rect{X: 0, Y: 0, W: w, H: h})
 }
 
+// Refresh draws whatever changed since the last Refresh. When nothing did the
+// frame isn't presented at all.
 func (window *Window) Refresh() {
 	window.updateSize()
+	window.Advance()
 
-	err := window.SdlRenderer.SetDrawColor(window.backgroundColor.R, window.backgroundColor.G, window.backgroundColor.B, window.backgroundColor.A)
-	if err != nil {
-		log.Fatal(err)
+	if !window.needsRedraw() && !window.floatingChanged() {
+		return
 	}
-	window.SdlRenderer.Clear()
 
-	if err := window.renderCells(); err != nil {
+	window.compose()
+	window.SdlRenderer.Present()
+	window.publishFrame()
+}
+
+// Advance moves animations, blinking decorations, floating glyphs, effects and
+// hit regions on to now without drawing. Refresh does this itself, windows that
+// are only read through Frame, like telnet sessions, call it once a frame.
+func (window *Window) Advance() {
+	window.hits.swap()
+	window.tidyEffects()
+	window.advanceAnimations()
+	window.floatingMoving = window.advanceFloating()
+}
+
+// compose draws the frame into the renderer's back buffer
+func (window *Window) compose() {
+	if err := window.renderFrame(); err != nil {
 		log.Println("Failed to render cells", err)
 	}
 
-	window.SdlRenderer.Present()
+	if err := window.renderFloating(); err != nil {
+		log.Println("Failed to render floating glyphs", err)
+	}
 
+	if err := window.renderOverlays(); err != nil {
+		log.Println("Failed to render effect overlays", err)
+	}
 }
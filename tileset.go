@@ -0,0 +1,92 @@
+package gterm
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Tileset describes a sprite sheet, the size of its cells and the runes each
+// cell draws. gterm-atlas writes one next to every sheet it makes.
+type Tileset struct {
+	// Image is the sheet's PNG, relative to the descriptor
+	Image      string
+	CellWidth  int
+	CellHeight int
+	Columns    int
+	Glyphs     map[rune]int
+}
+
+// LoadTileset reads a tileset descriptor and its sheet as an atlas
+func LoadTileset(path string) (*Atlas, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var tileset Tileset
+	if err := json.NewDecoder(file).Decode(&tileset); err != nil {
+		return nil, err
+	}
+	if tileset.CellWidth <= 0 || tileset.CellHeight <= 0 || tileset.Columns <= 0 {
+		return nil, errors.New("Tileset needs a cell size and column count")
+	}
+
+	sheetFile, err := os.Open(filepath.Join(filepath.Dir(path), tileset.Image))
+	if err != nil {
+		return nil, err
+	}
+	defer sheetFile.Close()
+
+	sheet, err := png.Decode(sheetFile)
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, sheet.Bounds().Dx(), sheet.Bounds().Dy()))
+	draw.Draw(rgba, rgba.Bounds(), sheet, sheet.Bounds().Min, draw.Src)
+
+	return &Atlas{
+		Image:      rgba,
+		CellWidth:  tileset.CellWidth,
+		CellHeight: tileset.CellHeight,
+		Columns:    tileset.Columns,
+		Glyphs:     tileset.Glyphs,
+	}, nil
+}
+
+// SaveTileset writes the atlas sheet as a PNG and a descriptor for it to path.
+// The sheet goes next to the descriptor, named the same but ending .png.
+func SaveTileset(path string, atlas *Atlas) error {
+	sheetPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".png"
+
+	sheet, err := os.Create(sheetPath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(sheet, atlas.Image); err != nil {
+		sheet.Close()
+		return err
+	}
+	if err := sheet.Close(); err != nil {
+		return err
+	}
+
+	descriptor, err := json.MarshalIndent(Tileset{
+		Image:      filepath.Base(sheetPath),
+		CellWidth:  atlas.CellWidth,
+		CellHeight: atlas.CellHeight,
+		Columns:    atlas.Columns,
+		Glyphs:     atlas.Glyphs,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(descriptor, '\n'), 0644)
+}
@@ -0,0 +1,49 @@
+package gterm
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTilesetRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gterm-tileset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	atlas := &Atlas{
+		Image:      image.NewRGBA(image.Rect(0, 0, 8, 4)),
+		CellWidth:  4,
+		CellHeight: 4,
+		Columns:    2,
+		Glyphs:     map[rune]int{'a': 0, '─': 1},
+	}
+	atlas.Image.Set(5, 2, color.RGBA{255, 255, 255, 255})
+
+	path := filepath.Join(dir, "tiles_4x4.json")
+	if err := SaveTileset(path, atlas); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tiles_4x4.png")); err != nil {
+		t.Fatal("Expected the sheet next to the descriptor", err)
+	}
+
+	loaded, err := LoadAtlas(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.CellWidth != 4 || loaded.CellHeight != 4 || loaded.Columns != 2 {
+		t.Errorf("Expected 4x4 cells in 2 columns, got %vx%v in %v", loaded.CellWidth, loaded.CellHeight, loaded.Columns)
+	}
+	if index, ok := loaded.Lookup('─'); !ok || index != 1 {
+		t.Errorf("Expected '─' in cell 1, got %v %v", index, ok)
+	}
+	if atlasGlyph(t, loaded, '─') != "....\n....\n.#..\n...." {
+		t.Errorf("Expected the sheet's pixels to survive, got\n%v", atlasGlyph(t, loaded, '─'))
+	}
+}
@@ -0,0 +1,151 @@
+package gterm
+
+// Viewport maps world coordinates onto a rectangle of screen cells. X and Y
+// are the world coordinates shown in the top left corner of the rectangle.
+type Viewport struct {
+	ScreenX int
+	ScreenY int
+	Width   int
+	Height  int
+
+	X int
+	Y int
+
+	// MapWidth and MapHeight are the world bounds the viewport is clamped to,
+	// zero disables clamping on that axis.
+	MapWidth  int
+	MapHeight int
+
+	// DeadzoneWidth and DeadzoneHeight size a box in the middle of the viewport
+	// the follow target may move around in without scrolling. Zero keeps the
+	// target centered.
+	DeadzoneWidth  int
+	DeadzoneHeight int
+}
+
+// NewViewport constructs a viewport covering width x height cells starting at (screenX, screenY)
+func NewViewport(screenX int, screenY int, width int, height int) *Viewport {
+	return &Viewport{
+		ScreenX: screenX,
+		ScreenY: screenY,
+		Width:   width,
+		Height:  height,
+	}
+}
+
+// SetBounds sets the size of the world the viewport is clamped to
+func (viewport *Viewport) SetBounds(mapWidth int, mapHeight int) {
+	viewport.MapWidth = mapWidth
+	viewport.MapHeight = mapHeight
+	viewport.clamp()
+}
+
+// CenterOn moves the viewport so (x, y) is in the middle, as far as the bounds allow
+func (viewport *Viewport) CenterOn(x int, y int) {
+	viewport.X = x - viewport.Width/2
+	viewport.Y = y - viewport.Height/2
+	viewport.clamp()
+}
+
+// Follow scrolls the viewport just far enough to keep (x, y) inside the deadzone
+func (viewport *Viewport) Follow(x int, y int) {
+	viewport.X = followAxis(viewport.X, x, viewport.Width, viewport.DeadzoneWidth)
+	viewport.Y = followAxis(viewport.Y, y, viewport.Height, viewport.DeadzoneHeight)
+	viewport.clamp()
+}
+
+func followAxis(origin int, target int, size int, deadzone int) int {
+	if deadzone > size {
+		deadzone = size
+	}
+	low := origin + (size-deadzone)/2
+	high := low + deadzone - 1
+	if deadzone <= 0 {
+		high = low
+	}
+
+	switch {
+	case target < low:
+		return origin - (low - target)
+	case target > high:
+		return origin + (target - high)
+	}
+	return origin
+}
+
+func clampAxis(origin int, size int, bound int) int {
+	if bound <= 0 {
+		return origin
+	}
+	if bound <= size {
+		// The whole map fits, keep it centered in the viewport
+		return (bound - size) / 2
+	}
+	if origin < 0 {
+		return 0
+	}
+	if origin > bound-size {
+		return bound - size
+	}
+	return origin
+}
+
+func (viewport *Viewport) clamp() {
+	viewport.X = clampAxis(viewport.X, viewport.Width, viewport.MapWidth)
+	viewport.Y = clampAxis(viewport.Y, viewport.Height, viewport.MapHeight)
+}
+
+// Scroll moves the viewport by (dx, dy) world cells, respecting the bounds
+func (viewport *Viewport) Scroll(dx int, dy int) {
+	viewport.X += dx
+	viewport.Y += dy
+	viewport.clamp()
+}
+
+// Contains reports whether the world position (x, y) is visible in the viewport
+func (viewport *Viewport) Contains(x int, y int) bool {
+	return x >= viewport.X && x < viewport.X+viewport.Width &&
+		y >= viewport.Y && y < viewport.Y+viewport.Height
+}
+
+// WorldToScreen converts a world position to a screen cell, ok is false when it is not visible
+func (viewport *Viewport) WorldToScreen(x int, y int) (col int, row int, ok bool) {
+	col = x - viewport.X + viewport.ScreenX
+	row = y - viewport.Y + viewport.ScreenY
+	return col, row, viewport.Contains(x, y)
+}
+
+// ScreenToWorld converts a screen cell to a world position, ok is false when the cell is outside the viewport
+func (viewport *Viewport) ScreenToWorld(col int, row int) (x int, y int, ok bool) {
+	x = col - viewport.ScreenX + viewport.X
+	y = row - viewport.ScreenY + viewport.Y
+	return x, y, viewport.Contains(x, y)
+}
+
+// VisibleBounds returns the world rectangle [minX, maxX) x [minY, maxY) shown by the
+// viewport, trimmed to the map bounds when they are set.
+func (viewport *Viewport) VisibleBounds() (minX int, minY int, maxX int, maxY int) {
+	minX, minY = viewport.X, viewport.Y
+	maxX, maxY = viewport.X+viewport.Width, viewport.Y+viewport.Height
+	if viewport.MapWidth > 0 {
+		minX, maxX = maxInt(minX, 0), minInt(maxX, viewport.MapWidth)
+	}
+	if viewport.MapHeight > 0 {
+		minY, maxY = maxInt(minY, 0), minInt(maxY, viewport.MapHeight)
+	}
+	return minX, minY, maxX, maxY
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package gterm
+
+import (
+	"testing"
+)
+
+func TestViewportCenterOnClampsToBounds(t *testing.T) {
+	viewport := NewViewport(0, 0, 20, 10)
+	viewport.SetBounds(100, 100)
+
+	viewport.CenterOn(50, 50)
+	if viewport.X != 40 || viewport.Y != 45 {
+		t.Errorf("Expected origin (40,45) but got (%v,%v)", viewport.X, viewport.Y)
+	}
+
+	viewport.CenterOn(2, 3)
+	if viewport.X != 0 || viewport.Y != 0 {
+		t.Errorf("Expected origin clamped to (0,0) but got (%v,%v)", viewport.X, viewport.Y)
+	}
+
+	viewport.CenterOn(99, 99)
+	if viewport.X != 80 || viewport.Y != 90 {
+		t.Errorf("Expected origin clamped to (80,90) but got (%v,%v)", viewport.X, viewport.Y)
+	}
+}
+
+func TestViewportSmallMapIsCentered(t *testing.T) {
+	viewport := NewViewport(0, 0, 20, 10)
+	viewport.SetBounds(10, 10)
+	viewport.CenterOn(9, 9)
+
+	if viewport.X != -5 || viewport.Y != 0 {
+		t.Errorf("Expected origin (-5,0) but got (%v,%v)", viewport.X, viewport.Y)
+	}
+}
+
+func TestViewportFollowDeadzone(t *testing.T) {
+	viewport := NewViewport(0, 0, 20, 10)
+	viewport.DeadzoneWidth = 6
+	viewport.DeadzoneHeight = 4
+	viewport.SetBounds(100, 100)
+	viewport.CenterOn(50, 50)
+
+	startX, startY := viewport.X, viewport.Y
+	viewport.Follow(52, 51)
+	if viewport.X != startX || viewport.Y != startY {
+		t.Errorf("Expected no scroll inside the deadzone but origin moved to (%v,%v)", viewport.X, viewport.Y)
+	}
+
+	viewport.Follow(55, 50)
+	if viewport.X != startX+3 {
+		t.Errorf("Expected to scroll 3 columns but origin is %v", viewport.X)
+	}
+}
+
+func TestViewportCoordinateConversion(t *testing.T) {
+	viewport := NewViewport(5, 2, 20, 10)
+	viewport.X, viewport.Y = 30, 40
+
+	col, row, ok := viewport.WorldToScreen(31, 42)
+	if !ok || col != 6 || row != 4 {
+		t.Errorf("Expected (6,4,true) but got (%v,%v,%v)", col, row, ok)
+	}
+
+	x, y, ok := viewport.ScreenToWorld(col, row)
+	if !ok || x != 31 || y != 42 {
+		t.Errorf("Expected (31,42,true) but got (%v,%v,%v)", x, y, ok)
+	}
+
+	if _, _, ok := viewport.WorldToScreen(29, 42); ok {
+		t.Errorf("Expected (29,42) to be outside the viewport")
+	}
+	if _, _, ok := viewport.ScreenToWorld(0, 0); ok {
+		t.Errorf("Expected screen (0,0) to be outside the viewport")
+	}
+}
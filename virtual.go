@@ -0,0 +1,68 @@
+package gterm
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// NewVirtualWindow constructs a window that is never shown on the desktop.
+// Only its cells are used, read back with CellAt or Text, so it needs neither a
+// font nor SDL to be initialized. Remote sessions and tests draw into these.
+func NewVirtualWindow(columns int, rows int) *Window {
+	return NewWindow(columns, rows, "", 1, 1, false)
+}
+
+// Resize changes how many columns and rows the grid has. Cells that still fit
+// keep their contents. A desktop window is resized to keep its cell size.
+func (window *Window) Resize(columns int, rows int) {
+	if columns == window.Columns && rows == window.Rows {
+		return
+	}
+
+	cells := make([]cell, columns*rows)
+	for row := 0; row < rows && row < window.Rows; row++ {
+		for col := 0; col < columns && col < window.Columns; col++ {
+			cells[col+row*columns] = window.cells[col+row*window.Columns]
+		}
+	}
+	window.cells = cells
+	window.drawn = nil
+	window.Columns = columns
+	window.Rows = rows
+	window.WidthPixel = columns * window.FontWPixel
+	window.HeightPixel = rows * window.FontHPixel
+
+	if window.SdlWindow != nil {
+		window.SdlWindow.SetSize(window.WidthPixel, window.HeightPixel)
+	}
+	window.resetTarget()
+}
+
+// CellAt returns what is showing in a cell, the glyph drawn on top with its
+// colours after lighting. The background is NoColor when the window's
+// background shows through.
+func (window *Window) CellAt(col int, row int) (glyph rune, fColor sdl.Color, bColor sdl.Color, err error) {
+	index, err := window.cellIndex(col, row)
+	if err != nil {
+		return ' ', NoColor, NoColor, err
+	}
+	cell := window.cells[index]
+
+	glyph, fColor, bColor = ' ', NoColor, cell.bgColor
+	if len(cell.renderItems) > 0 {
+		top := cell.renderItems[len(cell.renderItems)-1]
+		glyph, fColor = top.Glyph, top.FColor
+	}
+	if cell.animation != nil && cell.frame >= 0 {
+		frame := cell.animation.Frames[cell.frame]
+		glyph, fColor = frame.Glyph, frame.FColor
+		if frame.BColor != NoColor {
+			bColor = frame.BColor
+		}
+	}
+	return glyph, applyLight(fColor, cell.light), applyLight(bColor, cell.light), nil
+}
+
+// BackgroundColor is the colour shown behind cells without their own background
+func (window *Window) BackgroundColor() sdl.Color {
+	return window.backgroundColor
+}
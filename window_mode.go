@@ -0,0 +1,168 @@
+package gterm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// FullscreenMode selects how the window covers the display
+type FullscreenMode int
+
+const (
+	// Windowed is a regular desktop window
+	Windowed FullscreenMode = iota
+	// FullscreenDesktop covers the display at the desktop resolution
+	FullscreenDesktop
+	// FullscreenExclusive switches the display to the window's resolution
+	FullscreenExclusive
+)
+
+func (mode FullscreenMode) flags() uint32 {
+	switch mode {
+	case FullscreenDesktop:
+		return sdl.WINDOW_FULLSCREEN_DESKTOP
+	case FullscreenExclusive:
+		return sdl.WINDOW_FULLSCREEN
+	}
+	return 0
+}
+
+// Geometry is where the window sits on the desktop and how it is shown. The
+// position and size are always the windowed ones, even while fullscreen, so
+// restoring a saved geometry brings back the window the player last arranged.
+type Geometry struct {
+	X          int
+	Y          int
+	Width      int
+	Height     int
+	Fullscreen FullscreenMode
+	Borderless bool
+}
+
+// SetHighDPI asks for a full resolution drawable on high-DPI displays. It must
+// be called before Init.
+func (window *Window) SetHighDPI(enabled bool) {
+	window.highDPI = enabled
+}
+
+// SetBorderless removes or restores the window decorations
+func (window *Window) SetBorderless(borderless bool) {
+	window.geometry.Borderless = borderless
+	if window.SdlWindow != nil {
+		window.SdlWindow.SetBordered(!borderless)
+	}
+}
+
+// SetFullscreen switches between windowed and fullscreen modes
+func (window *Window) SetFullscreen(mode FullscreenMode) error {
+	if window.SdlWindow == nil {
+		window.geometry.Fullscreen = mode
+		return nil
+	}
+
+	if window.geometry.Fullscreen == Windowed {
+		window.recordWindowedGeometry()
+	}
+	if err := window.SdlWindow.SetFullscreen(mode.flags()); err != nil {
+		return err
+	}
+	window.geometry.Fullscreen = mode
+
+	if mode == Windowed {
+		// Some platforms forget the windowed size on the way back
+		window.SdlWindow.SetSize(window.geometry.Width, window.geometry.Height)
+		window.SdlWindow.SetPosition(window.geometry.X, window.geometry.Y)
+	}
+	window.updateSize()
+	return nil
+}
+
+// Fullscreen reports the current fullscreen mode
+func (window *Window) Fullscreen() FullscreenMode {
+	return window.geometry.Fullscreen
+}
+
+func (window *Window) recordWindowedGeometry() {
+	window.geometry.X, window.geometry.Y = window.SdlWindow.GetPosition()
+	window.geometry.Width, window.geometry.Height = window.SdlWindow.GetSize()
+}
+
+// Geometry returns the window's current geometry, ready to be saved
+func (window *Window) Geometry() Geometry {
+	if window.SdlWindow != nil && window.geometry.Fullscreen == Windowed {
+		window.recordWindowedGeometry()
+	}
+	return window.geometry
+}
+
+// RestoreGeometry moves and resizes the window to a saved geometry. Before
+// Init it sets where the window will be created.
+func (window *Window) RestoreGeometry(geometry Geometry) error {
+	if geometry.Width <= 0 || geometry.Height <= 0 {
+		geometry.Width, geometry.Height = window.WidthPixel, window.HeightPixel
+	}
+	window.geometry.X, window.geometry.Y = geometry.X, geometry.Y
+	window.geometry.Width, window.geometry.Height = geometry.Width, geometry.Height
+	window.geometryRestored = true
+
+	if window.SdlWindow == nil {
+		window.geometry.Fullscreen = geometry.Fullscreen
+		window.geometry.Borderless = geometry.Borderless
+		return nil
+	}
+
+	window.SetBorderless(geometry.Borderless)
+	if window.geometry.Fullscreen == Windowed {
+		window.SdlWindow.SetSize(geometry.Width, geometry.Height)
+		window.SdlWindow.SetPosition(geometry.X, geometry.Y)
+	}
+	return window.SetFullscreen(geometry.Fullscreen)
+}
+
+// createWindow opens the SDL window using the requested geometry and modes
+func (window *Window) createWindow() (*sdl.Window, error) {
+	var flags uint32 = sdl.WINDOW_SHOWN | sdl.WINDOW_RESIZABLE
+	if window.highDPI {
+		flags |= sdl.WINDOW_ALLOW_HIGHDPI
+	}
+	if window.geometry.Borderless {
+		flags |= sdl.WINDOW_BORDERLESS
+	}
+	flags |= window.geometry.Fullscreen.flags()
+
+	x, y := sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED
+	w, h := window.WidthPixel, window.HeightPixel
+	if window.geometryRestored {
+		x, y = window.geometry.X, window.geometry.Y
+		w, h = window.geometry.Width, window.geometry.Height
+	} else {
+		window.geometry.Width, window.geometry.Height = w, h
+	}
+
+	return sdl.CreateWindow("", x, y, w, h, flags)
+}
+
+// SaveGeometry writes a geometry to path as JSON
+func SaveGeometry(path string, geometry Geometry) error {
+	data, err := json.MarshalIndent(geometry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadGeometry reads a geometry written by SaveGeometry
+func LoadGeometry(path string) (Geometry, error) {
+	var geometry Geometry
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return geometry, err
+	}
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return geometry, fmt.Errorf("Failed to read window geometry from %s: %v", path, err)
+	}
+	return geometry, nil
+}
@@ -0,0 +1,62 @@
+package gterm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGeometryRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gterm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "geometry.json")
+	expected := Geometry{X: 10, Y: 20, Width: 960, Height: 720, Fullscreen: FullscreenDesktop, Borderless: true}
+	if err := SaveGeometry(path, expected); err != nil {
+		t.Fatalf("Failed to save geometry: %v", err)
+	}
+
+	geometry, err := LoadGeometry(path)
+	if err != nil {
+		t.Fatalf("Failed to load geometry: %v", err)
+	}
+	if geometry != expected {
+		t.Errorf("Expected %+v but got %+v", expected, geometry)
+	}
+}
+
+func TestFullscreenKeepsWindowedGeometry(t *testing.T) {
+	os.Setenv("SDL_VIDEODRIVER", "dummy")
+	window := NewWindow(10, 5, filepath.Join("example", "atlas", "fonts", "cp437_12x12.png"), 12, 12, false)
+	if err := window.Init(); err != nil {
+		t.Skipf("SDL dummy video driver unavailable: %v", err)
+	}
+	defer window.SdlWindow.Destroy()
+	defer window.SdlRenderer.Destroy()
+
+	if err := window.SetFullscreen(FullscreenDesktop); err != nil {
+		t.Fatalf("Failed to go fullscreen: %v", err)
+	}
+	if window.Fullscreen() != FullscreenDesktop {
+		t.Errorf("Expected FullscreenDesktop but got %v", window.Fullscreen())
+	}
+
+	geometry := window.Geometry()
+	if geometry.Width != 120 || geometry.Height != 60 {
+		t.Errorf("Expected windowed size 120x60 to be remembered but got %vx%v", geometry.Width, geometry.Height)
+	}
+
+	if err := window.SetFullscreen(Windowed); err != nil {
+		t.Fatalf("Failed to leave fullscreen: %v", err)
+	}
+	if w, h := window.SdlWindow.GetSize(); w != 120 || h != 60 {
+		t.Errorf("Expected window to return to 120x60 but got %vx%v", w, h)
+	}
+	if window.DisplayWPixel != 12 || window.DisplayHPixel != 12 {
+		t.Errorf("Expected 12x12 cells but got %vx%v", window.DisplayWPixel, window.DisplayHPixel)
+	}
+}